@@ -3,6 +3,7 @@ package base
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -29,6 +30,25 @@ const (
 	A100TolerationKey = "gpu-series"
 )
 
+// PriorityClasses returns the priority classes referenced by the scheduling
+// strategies, they must exist in every region.
+func PriorityClasses() []string {
+	classes := []string{}
+	for _, class := range []string{CPUPriorityClass, GPUPriorityClass, HPCPriorityClass, VGPUPriorityClass, A800PriorityClass} {
+		found := false
+		for _, c := range classes {
+			if c == class {
+				found = true
+				break
+			}
+		}
+		if !found {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
 type Resource struct {
 	CPUNum           uint   `json:"cpu_num" gorm:"not null;default:1"`
 	GPUNum           uint   `json:"gpu_num" gorm:"not null;default:0"`
@@ -391,3 +411,45 @@ func maxUInt(a, b uint) uint {
 	}
 	return b
 }
+
+// ConvertResourceListToResource is the reverse of Resource.ResourceList, cpu
+// is rounded up to cores, memory and ephemeral storage to Gi. The names may
+// carry the "requests." prefix of a resource quota, which wins over the bare
+// name, the "limits." names are ignored.
+func ConvertResourceListToResource(list v1.ResourceList) Resource {
+	r := Resource{}
+	for name, quantity := range list {
+		if _, ok := list["requests."+name]; ok || strings.HasPrefix(string(name), "limits.") {
+			continue
+		}
+		r.set(v1.ResourceName(strings.TrimPrefix(string(name), "requests.")), quantity)
+	}
+	return r
+}
+
+func (r *Resource) set(name v1.ResourceName, quantity resource.Quantity) {
+	switch name {
+	case v1.ResourceCPU:
+		r.CPUNum = uint(quantity.Value())
+	case v1.ResourceMemory:
+		r.MemorySize = uint(divideRoundUp(quantity.Value(), 1<<30))
+	case v1.ResourceEphemeralStorage:
+		r.EphemeralStorage = uint(divideRoundUp(quantity.Value(), 1<<30))
+	case ResourceNvidiaGPU:
+		r.GPUNum = uint(quantity.Value())
+	case ResourceVendorVGPU:
+		r.GPUNum = uint(quantity.Value())
+		r.GPUSeries = "vGPU"
+	case ResourceVendorGPUPercent:
+		r.GPUPercent = uint(quantity.Value())
+	case ResourceVendorGPUMem:
+		r.GPUMem = uint(quantity.Value())
+	}
+}
+
+func divideRoundUp(a, b int64) int64 {
+	if a <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
@@ -0,0 +1,214 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/06 10:05:44
+ Desc     : builders against the fake clientset
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type crudBuilder interface {
+	Create() error
+	Update() error
+	Delete() error
+	CreateOrUpdate() error
+}
+
+// testCRUD runs the builder through its whole lifecycle, get fetches the
+// object from the clientset.
+func testCRUD(t *testing.T, builder crudBuilder, get func() error) {
+	t.Helper()
+	if err := get(); !errors.IsNotFound(err) {
+		t.Fatalf("expect not found before create, got %v", err)
+	}
+	if err := builder.CreateOrUpdate(); err != nil {
+		t.Fatalf("create or update (create) error: %v", err)
+	}
+	if err := get(); err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+	if err := builder.Create(); !errors.IsAlreadyExists(err) {
+		t.Fatalf("expect already exists, got %v", err)
+	}
+	if err := builder.Update(); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if err := builder.CreateOrUpdate(); err != nil {
+		t.Fatalf("create or update (update) error: %v", err)
+	}
+	if err := builder.Delete(); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	if err := get(); !errors.IsNotFound(err) {
+		t.Fatalf("expect not found after delete, got %v", err)
+	}
+}
+
+func TestBuildersWithFakeClient(t *testing.T) {
+	ctx := context.TODO()
+	labels := map[string]string{"app": "demo"}
+	container := NewContainer(ctx).Metadata("demo").Image("nginx")
+	template := NewPodTemplate(ctx).Labels(labels).Container(*container)
+
+	cases := []struct {
+		name string
+		new  func(client *fake.Clientset) (crudBuilder, func() error)
+	}{
+		{"Pod", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewPod(ctx).LinkClient(client).Metadata("demo", "default").Labels(labels).Container(*container.Container)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Deployment", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewDeployment(ctx).LinkClient(client).Metadata("demo", "default").Replicas(2).Selector(labels).Template(template)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"StatefulSet", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewStatefulSet(ctx).LinkClient(client).Metadata("demo", "default").Replicas(2).Selector(labels).
+				ServiceName("demo").Template(template).UpdateStrategy(appsv1.RollingUpdateStatefulSetStrategyType, 1)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Job", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewJob(ctx).LinkClient(client).Metadata("demo", "default").Template(template).Completions(2).Parallelism(2)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"CronJob", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewCronJob(ctx).LinkClient(client).Metadata("demo", "default").Schedule("*/5 * * * *").Template(template)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"DaemonSet", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewDaemonSet(ctx).LinkClient(client).Metadata("demo", "default").Selector(labels).Template(template)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Service", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewService(ctx).LinkClient(client).Metadata("demo", "default").Port("http", 80, 0, v1.ProtocolTCP).Selector(labels)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Secret", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewSecret(ctx).LinkClient(client).Metadata("demo", "default").StringData(map[string]string{"token": "abc"})
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"ConfigMap", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewConfigMap(ctx).LinkClient(client).Metadata("demo", "default").Data(map[string]string{"key": "value"})
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Node", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewNode(ctx).LinkClient(client).Metadata("node-1").Labels(labels)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Endpoint", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewEndpoint(ctx).LinkClient(client).Metadata("demo", "default")
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"PersistentVolumeClaim", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewPersistentVolumeClaim(ctx).LinkClient(client).Metadata("demo", "default").StorageClass("nfs").
+				AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}).Size(resource.MustParse("10Gi"))
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"PersistentVolume", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewPersistentVolume(ctx).LinkClient(client).Metadata("demo").Capacity(resource.MustParse("10Gi")).
+				AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}).NFS("10.0.0.1", "/data", false)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Ingress", func(client *fake.Clientset) (crudBuilder, func() error) {
+			service := NewService(ctx).Metadata("notebook", "default")
+			b := NewIngress(ctx).LinkClient(client).Metadata("notebook", "default").Class("nginx").
+				Rule("notebook.example.com", "/", networkingv1.PathTypePrefix, service, intstr.FromString("http"))
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"NetworkPolicy", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewNetworkPolicy(ctx).LinkClient(client).Metadata("isolation", "default").AllowSameNamespaceOnly()
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"Role", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewRole(ctx).LinkClient(client).Metadata("reader", "default").
+				Rule([]string{"get", "list"}, []string{""}, []string{"pods"})
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"RoleBinding", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewRoleBinding(ctx).LinkClient(client).Metadata("reader", "default").
+				Subject("ServiceAccount", "", "default", "default").RoleRef("Role", "rbac.authorization.k8s.io", "reader")
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"HPA", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewHPA(ctx).LinkClient(client).Metadata("demo", "default").
+				TargetDeployment(NewDeployment(ctx).Metadata("demo", "default")).Replicas(1, 4).
+				ResourceUtilization(v1.ResourceCPU, 80)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"PodDisruptionBudget", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewPodDisruptionBudget(ctx).LinkClient(client).Metadata("demo", "default").
+				MinAvailable(intstr.FromString("50%")).Selector(map[string]string{"app": "demo"})
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"PriorityClass", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewPriorityClass(ctx).LinkClient(client).Metadata("demo").Value(1000).
+				PreemptionPolicy(v1.PreemptNever).Description("demo")
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"EndpointSlice", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewEndpointSlice(ctx).LinkClient(client).Metadata("demo-abc", "default").Service("demo").
+				Endpoint([]string{"10.0.0.1"}, "", "", discoveryv1.EndpointConditions{}).Port("http", 80, v1.ProtocolTCP)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"ReplicaSet", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewReplicaSet(ctx).LinkClient(client).Metadata("demo", "default").
+				Replicas(2).Selector(map[string]string{"app": "demo"}).MinReadySeconds(5)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"ServiceAccount", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewServiceAccount(ctx).LinkClient(client).Metadata("demo", "default").AutomountServiceAccountToken(false)
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"ClusterRole", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewClusterRole(ctx).LinkClient(client).Metadata("demo").Rule([]string{"get"}, []string{""}, []string{"pods"})
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+		{"ClusterRoleBinding", func(client *fake.Clientset) (crudBuilder, func() error) {
+			b := NewClusterRoleBinding(ctx).LinkClient(client).Metadata("demo", "").
+				Subject("ServiceAccount", "", "demo", "default").RoleRef("ClusterRole", "rbac.authorization.k8s.io", "demo")
+			return b, func() error { _, err := b.Get(); return err }
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder, get := c.new(fake.NewSimpleClientset())
+			testCRUD(t, builder, get)
+		})
+	}
+}
+
+func TestDeploymentUpdateWithFakeClient(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deploy := NewDeployment(nil).LinkClient(client).Metadata("demo", "default").Replicas(1)
+	if err := deploy.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := deploy.Replicas(3).CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	replicas, err := deploy.GetReplicas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 3 {
+		t.Errorf("expect 3 replicas, got %d", replicas)
+	}
+	if err := deploy.Rollout(); err != nil {
+		t.Errorf("rollout error: %v", err)
+	}
+}
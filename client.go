@@ -12,27 +12,78 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
-	"time"
 
-	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	typev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 type LinkInfo struct {
 	Region string `json:"region"`
 	Config string `json:"config"`
+	// err keeps the failure of the last Link so that the builder methods
+	// can return it instead of panicking
+	err error
+}
+
+// Err returns the error of the last Link, nil if the client is ready.
+func (l *LinkInfo) Err() error {
+	return l.err
 }
 
 type KubeClient struct {
-	*kubernetes.Clientset
+	kubernetes.Interface
+	// dynamic and mapper serve the resources without a typed client, e.g.
+	// CRDs, they are nil unless set by WithDynamic
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+	// config connects the streams which are not served by the clientset,
+	// e.g. exec, it is nil unless set by WithRESTConfig
+	config *rest.Config
+}
+
+// NewKubeClientFor wraps an existing clientset, e.g. the fake clientset of
+// k8s.io/client-go/kubernetes/fake in unit tests.
+func NewKubeClientFor(client kubernetes.Interface) *KubeClient {
+	return &KubeClient{Interface: client}
+}
+
+// WithDynamic sets the dynamic client and the mapper which resolves the
+// resource of a kind.
+func (cs *KubeClient) WithDynamic(client dynamic.Interface, mapper meta.RESTMapper) *KubeClient {
+	cs.dynamic, cs.mapper = client, mapper
+	return cs
+}
+
+func (cs *KubeClient) Dynamic() dynamic.Interface {
+	return cs.dynamic
+}
+
+func (cs *KubeClient) RESTMapper() meta.RESTMapper {
+	return cs.mapper
 }
 
-func NewKubeClient(region, kubeconfig string) *KubeClient {
-	return &KubeClient{getClient(region, kubeconfig)}
+// WithRESTConfig sets the rest config the clientset is built from.
+func (cs *KubeClient) WithRESTConfig(config *rest.Config) *KubeClient {
+	cs.config = config
+	return cs
+}
+
+func (cs *KubeClient) RESTConfig() *rest.Config {
+	return cs.config
+}
+
+// NewKubeClient returns the client of the region from the registry given by
+// WithRegistry, or the default registry.
+func NewKubeClient(region, kubeconfig string, opts ...ClientOption) (*KubeClient, error) {
+	options := newClientOptions(opts...)
+	registry := options.registry
+	if registry == nil {
+		registry = defaultRegistry
+	}
+	return registry.client(region, kubeconfig, options)
 }
 
 func (cs *KubeClient) Namespaces() typev1.NamespaceInterface {
@@ -59,59 +110,6 @@ func (cs *KubeClient) ResourceQuotas(namespace string) typev1.ResourceQuotaInter
 // 	return &ClientSet{clientset}
 // }
 
-func BuildClusterConfig(kubeconfig string) (*rest.Config, error) {
-	if kubeconfig == "" {
-		return rest.InClusterConfig()
-	}
-	var cfg rest.Config
-	if err := yaml.Unmarshal([]byte(kubeconfig), &cfg); err != nil {
-		return clientcmd.BuildConfigFromFlags("", kubeconfig)
-	}
-	// kubeconfig := parsePath(region)
-	configPath := fmt.Sprintf("/tmp/%d", time.Now().Nanosecond())
-	if err := os.WriteFile(configPath, []byte(kubeconfig), 0644); err != nil {
-		return nil, err
-	}
-	rest, err := clientcmd.BuildConfigFromFlags("", configPath)
-	os.Remove(configPath)
-	return rest, err
-}
-
-var cs = clientSet{}
-
-type clientSet struct {
-	lock       sync.Mutex
-	clientsets map[string]*kubernetes.Clientset
-}
-
-func getClient(region, kubeconfig string) *kubernetes.Clientset {
-	if cs.clientsets == nil {
-		cs.clientsets = make(map[string]*kubernetes.Clientset)
-	}
-	// TODO: set QPS and Burst
-	// qps := viper.GetFloat64("api-server-qps")
-	// burst := viper.GetInt("api-server-burst")
-	if client, ok := cs.clientsets[region]; ok {
-		return client
-	} else {
-		cfg, err := BuildClusterConfig(kubeconfig)
-		if err != nil {
-			panic(err.Error())
-		}
-		// cfg.QPS = float32(qps)
-		// cfg.Burst = burst
-		client, err := kubernetes.NewForConfig(cfg)
-		if err != nil {
-			panic(err.Error())
-		}
-		// map concurrency write
-		cs.lock.Lock()
-		cs.clientsets[region] = client
-		cs.lock.Unlock()
-		return client
-	}
-}
-
 func parsePath(region string) string {
 	path := os.Getenv("KUBECONFIG")
 	if strings.HasPrefix(path, "") {
@@ -0,0 +1,59 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/03 16:21:37
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+)
+
+func TestLinkError(t *testing.T) {
+	registry := WithRegistry(NewClientRegistry())
+	if _, err := NewKubeClient("dev", "/not/exist/kubeconfig", registry); err == nil {
+		t.Fatal("expect error of missing kubeconfig file")
+	}
+
+	deployment := NewDeployment(nil).Link("dev", "/not/exist/kubeconfig", registry).Metadata("demo", "default")
+	secret := NewSecret(nil).Link("dev", "/not/exist/kubeconfig", registry).Metadata("demo", "default")
+	pod := NewPod(nil).Link("dev", "/not/exist/kubeconfig", registry).Metadata("demo", "default")
+	for name, err := range map[string]error{
+		"Deployment": deployment.Err(),
+		"Secret":     secret.Err(),
+		"Pod":        pod.Err(),
+	} {
+		if err == nil {
+			t.Fatalf("expect link error of %s", name)
+		}
+	}
+
+	// the builders return the link error instead of using the nil client
+	if err := deployment.Create(); err != deployment.Err() {
+		t.Errorf("expect link error from Create, got %v", err)
+	}
+	if _, err := deployment.Get(); err != deployment.Err() {
+		t.Errorf("expect link error from Get, got %v", err)
+	}
+	if deployment.Equal(nil) {
+		t.Error("expect not equal with link error")
+	}
+	if err := secret.Create(); err != secret.Err() {
+		t.Errorf("expect link error from Create, got %v", err)
+	}
+	if _, err := secret.Get(); err != secret.Err() {
+		t.Errorf("expect link error from Get, got %v", err)
+	}
+	if secret.Equal(nil) {
+		t.Error("expect not equal with link error")
+	}
+	if err := pod.Create(); err != pod.Err() {
+		t.Errorf("expect link error from Create, got %v", err)
+	}
+	if _, err := pod.Get(); err != pod.Err() {
+		t.Errorf("expect link error from Get, got %v", err)
+	}
+}
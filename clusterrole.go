@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type ClusterRole struct {
@@ -69,10 +70,16 @@ func (cr *ClusterRole) Annotations(annotations map[string]string) *ClusterRole {
 	return cr
 }
 
-func (cr *ClusterRole) Link(region, config string) *ClusterRole {
+func (cr *ClusterRole) Link(region, config string, opts ...ClientOption) *ClusterRole {
 	cr.Region = region
 	cr.Config = config
-	cr.client = NewKubeClient(region, config)
+	cr.client, cr.err = NewKubeClient(region, config, opts...)
+	return cr
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (cr *ClusterRole) LinkClient(client kubernetes.Interface) *ClusterRole {
+	cr.client, cr.err = NewKubeClientFor(client), nil
 	return cr
 }
 func (cr *ClusterRole) Rule(verbs []string, apiGroups []string, resoueces []string) *ClusterRole {
@@ -106,31 +113,49 @@ func (cr *ClusterRole) AggregationRule(matchLabels map[string]string) *ClusterRo
 }
 
 func (cr *ClusterRole) Create() error {
+	if cr.err != nil {
+		return cr.err
+	}
 	_, err := cr.client.RbacV1().ClusterRoles().Create(cr.ctx,
 		cr.ClusterRole, metav1.CreateOptions{})
 	return err
 }
 
 func (cr *ClusterRole) Update() error {
+	if cr.err != nil {
+		return cr.err
+	}
 	_, err := cr.client.RbacV1().ClusterRoles().Update(cr.ctx,
 		cr.ClusterRole, metav1.UpdateOptions{})
 	return err
 }
 
 func (cr *ClusterRole) Delete() error {
+	if cr.err != nil {
+		return cr.err
+	}
 	return cr.client.RbacV1().ClusterRoles().Delete(cr.ctx, cr.Name, metav1.DeleteOptions{})
 }
 
 func (cr *ClusterRole) Get() (*v1.ClusterRole, error) {
+	if cr.err != nil {
+		return nil, cr.err
+	}
 	return cr.client.RbacV1().ClusterRoles().Get(cr.ctx, cr.Name, metav1.GetOptions{})
 }
 
 func (cr *ClusterRole) Empty() bool {
+	if cr.err != nil {
+		return false
+	}
 	_, err := cr.client.RbacV1().ClusterRoles().Get(cr.ctx, cr.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (cr *ClusterRole) CreateOrUpdate() error {
+	if cr.err != nil {
+		return cr.err
+	}
 	_, err := cr.client.RbacV1().ClusterRoles().Get(cr.ctx, cr.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -142,10 +167,16 @@ func (cr *ClusterRole) CreateOrUpdate() error {
 }
 
 func (cr *ClusterRole) List() (*v1.ClusterRoleList, error) {
+	if cr.err != nil {
+		return nil, cr.err
+	}
 	return cr.client.RbacV1().ClusterRoles().List(cr.ctx, metav1.ListOptions{})
 }
 
 func (cr *ClusterRole) Equal(keys []string) bool {
+	if cr.err != nil {
+		return false
+	}
 	clusterRole, err := cr.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type ClusterRoleBinding struct {
@@ -49,10 +50,16 @@ func (crb *ClusterRoleBinding) Metadata(name, namespace string) *ClusterRoleBind
 	return crb
 }
 
-func (crb *ClusterRoleBinding) Link(region, config string) *ClusterRoleBinding {
+func (crb *ClusterRoleBinding) Link(region, config string, opts ...ClientOption) *ClusterRoleBinding {
 	crb.Region = region
 	crb.Config = config
-	crb.client = NewKubeClient(region, config)
+	crb.client, crb.err = NewKubeClient(region, config, opts...)
+	return crb
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (crb *ClusterRoleBinding) LinkClient(client kubernetes.Interface) *ClusterRoleBinding {
+	crb.client, crb.err = NewKubeClientFor(client), nil
 	return crb
 }
 func (crb *ClusterRoleBinding) Labels(labels map[string]string) *ClusterRoleBinding {
@@ -92,34 +99,55 @@ func (crb *ClusterRoleBinding) RoleRef(kind, apigroup, name string) *ClusterRole
 }
 
 func (crb *ClusterRoleBinding) Create() error {
-	_, err := crb.client.Clientset.RbacV1().ClusterRoleBindings().Create(crb.ctx, crb.ClusterRoleBinding, metav1.CreateOptions{})
+	if crb.err != nil {
+		return crb.err
+	}
+	_, err := crb.client.RbacV1().ClusterRoleBindings().Create(crb.ctx, crb.ClusterRoleBinding, metav1.CreateOptions{})
 	return err
 }
 
 func (crb *ClusterRoleBinding) Get() (*v1.ClusterRoleBinding, error) {
-	return crb.client.Clientset.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
+	if crb.err != nil {
+		return nil, crb.err
+	}
+	return crb.client.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
 }
 
 func (crb *ClusterRoleBinding) Delete() error {
-	return crb.client.Clientset.RbacV1().ClusterRoleBindings().Delete(crb.ctx, crb.Name, metav1.DeleteOptions{})
+	if crb.err != nil {
+		return crb.err
+	}
+	return crb.client.RbacV1().ClusterRoleBindings().Delete(crb.ctx, crb.Name, metav1.DeleteOptions{})
 }
 
 func (crb *ClusterRoleBinding) Update() error {
-	_, err := crb.client.Clientset.RbacV1().ClusterRoleBindings().Update(crb.ctx, crb.ClusterRoleBinding, metav1.UpdateOptions{})
+	if crb.err != nil {
+		return crb.err
+	}
+	_, err := crb.client.RbacV1().ClusterRoleBindings().Update(crb.ctx, crb.ClusterRoleBinding, metav1.UpdateOptions{})
 	return err
 }
 
 func (crb *ClusterRoleBinding) Empty() bool {
-	_, err := crb.client.Clientset.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
+	if crb.err != nil {
+		return false
+	}
+	_, err := crb.client.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (crb *ClusterRoleBinding) List() (*v1.ClusterRoleBindingList, error) {
-	return crb.client.Clientset.RbacV1().ClusterRoleBindings().List(crb.ctx, metav1.ListOptions{})
+	if crb.err != nil {
+		return nil, crb.err
+	}
+	return crb.client.RbacV1().ClusterRoleBindings().List(crb.ctx, metav1.ListOptions{})
 }
 
 func (crb *ClusterRoleBinding) CreateOrUpdate() error {
-	_, err := crb.client.Clientset.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
+	if crb.err != nil {
+		return crb.err
+	}
+	_, err := crb.client.RbacV1().ClusterRoleBindings().Get(crb.ctx, crb.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return crb.Create()
@@ -130,6 +158,9 @@ func (crb *ClusterRoleBinding) CreateOrUpdate() error {
 }
 
 func (crb *ClusterRoleBinding) Equal(keys []string) bool {
+	if crb.err != nil {
+		return false
+	}
 	clusterRoleBinding, err := crb.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
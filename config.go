@@ -0,0 +1,119 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/04 15:31:08
+ Desc     : build rest config from in-cluster, kubeconfig path or content
+*/
+
+package kube
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type ConfigSource string
+
+const (
+	ConfigSourceInCluster ConfigSource = "in-cluster"
+	ConfigSourceFile      ConfigSource = "file"
+	ConfigSourceContent   ConfigSource = "content"
+)
+
+const inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// ClusterConfig is the rest config together with where it was loaded from.
+type ClusterConfig struct {
+	*rest.Config
+	Source    ConfigSource
+	Context   string
+	Namespace string
+}
+
+// BuildClusterConfig builds the rest config, kubeconfig can be empty for
+// in-cluster config, a path of the kubeconfig file or the kubeconfig content.
+func BuildClusterConfig(kubeconfig string) (*rest.Config, error) {
+	cfg, err := LoadClusterConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Config, nil
+}
+
+// LoadClusterConfig is like BuildClusterConfig, the context and namespace
+// can be chosen by WithContext and WithNamespace.
+func LoadClusterConfig(kubeconfig string, opts ...ClientOption) (*ClusterConfig, error) {
+	return loadClusterConfig(kubeconfig, newClientOptions(opts...))
+}
+
+func loadClusterConfig(kubeconfig string, options clientOptions) (*ClusterConfig, error) {
+	if kubeconfig == "" {
+		cfg, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+		namespace := options.namespace
+		if namespace == "" {
+			if data, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+				namespace = strings.TrimSpace(string(data))
+			}
+		}
+		options.apply(cfg)
+		return &ClusterConfig{
+			Config:    cfg,
+			Source:    ConfigSourceInCluster,
+			Namespace: namespace,
+		}, nil
+	}
+	var (
+		raw    *clientcmdapi.Config
+		source ConfigSource
+		err    error
+	)
+	if isKubeconfigContent(kubeconfig) {
+		source = ConfigSourceContent
+		raw, err = clientcmd.Load([]byte(kubeconfig))
+	} else {
+		source = ConfigSourceFile
+		raw, err = clientcmd.LoadFromFile(kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig from %s error: %w", source, err)
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: options.context}
+	overrides.Context.Namespace = options.namespace
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(*raw, options.context, overrides, nil)
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("build config from %s error: %w", source, err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("get namespace from %s error: %w", source, err)
+	}
+	context := options.context
+	if context == "" {
+		context = raw.CurrentContext
+	}
+	options.apply(cfg)
+	return &ClusterConfig{
+		Config:    cfg,
+		Source:    source,
+		Context:   context,
+		Namespace: namespace,
+	}, nil
+}
+
+// isKubeconfigContent reports whether kubeconfig is the content rather than
+// a file path, the content of a kubeconfig always spans multiple lines
+// or is json.
+func isKubeconfigContent(kubeconfig string) bool {
+	return strings.Contains(kubeconfig, "\n") ||
+		strings.HasPrefix(strings.TrimSpace(kubeconfig), "{")
+}
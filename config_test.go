@@ -0,0 +1,81 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/04 16:02:51
+ Desc     :
+*/
+
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: admin
+    namespace: dev-ns
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+current-context: dev
+users:
+- name: admin
+  user:
+    token: secret-token
+`
+
+func TestLoadClusterConfig(t *testing.T) {
+	cfg, err := LoadClusterConfig(testKubeconfig)
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+	if cfg.Source != ConfigSourceContent || cfg.Context != "dev" || cfg.Namespace != "dev-ns" {
+		t.Errorf("unexpected config: source %s, context %s, namespace %s", cfg.Source, cfg.Context, cfg.Namespace)
+	}
+	if cfg.Host != "https://dev.example.com:6443" || cfg.BearerToken != "secret-token" {
+		t.Errorf("unexpected host %s or token %s", cfg.Host, cfg.BearerToken)
+	}
+
+	cfg, err = LoadClusterConfig(testKubeconfig, WithContext("prod"), WithNamespace("team-a"), WithQPS(50), WithBurst(100))
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+	if cfg.Host != "https://prod.example.com:6443" || cfg.Context != "prod" || cfg.Namespace != "team-a" {
+		t.Errorf("unexpected host %s, context %s, namespace %s", cfg.Host, cfg.Context, cfg.Namespace)
+	}
+	if cfg.QPS != 50 || cfg.Burst != 100 {
+		t.Errorf("unexpected qps %v or burst %d", cfg.QPS, cfg.Burst)
+	}
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadClusterConfig(path)
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+	if cfg.Source != ConfigSourceFile {
+		t.Errorf("unexpected source %s", cfg.Source)
+	}
+
+	if _, err := LoadClusterConfig("/not/exist/kubeconfig"); err == nil {
+		t.Error("expect error of missing kubeconfig file")
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type ConfigMap struct {
@@ -43,10 +44,16 @@ func NewConfigMap(ctx context.Context) *ConfigMap {
 	}
 }
 
-func (c *ConfigMap) Link(region, config string) *ConfigMap {
+func (c *ConfigMap) Link(region, config string, opts ...ClientOption) *ConfigMap {
 	c.Region = region
 	c.Config = config
-	c.client = NewKubeClient(region, config)
+	c.client, c.err = NewKubeClient(region, config, opts...)
+	return c
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (c *ConfigMap) LinkClient(client kubernetes.Interface) *ConfigMap {
+	c.client, c.err = NewKubeClientFor(client), nil
 	return c
 }
 
@@ -86,27 +93,42 @@ func (c *ConfigMap) Annotations(annotations map[string]string) *ConfigMap {
 }
 
 func (c *ConfigMap) Create() error {
+	if c.err != nil {
+		return c.err
+	}
 	configmaps := c.client.CoreV1().ConfigMaps(c.Namespace)
 	_, err := configmaps.Create(c.ctx, c.ConfigMap, metav1.CreateOptions{})
 	return err
 }
 
 func (c *ConfigMap) Delete() error {
+	if c.err != nil {
+		return c.err
+	}
 	configmaps := c.client.CoreV1().ConfigMaps(c.Namespace)
 	return configmaps.Delete(c.ctx, c.Name, metav1.DeleteOptions{})
 }
 
 func (c *ConfigMap) Update() error {
+	if c.err != nil {
+		return c.err
+	}
 	configmaps := c.client.CoreV1().ConfigMaps(c.Namespace)
 	_, err := configmaps.Update(c.ctx, c.ConfigMap, metav1.UpdateOptions{})
 	return err
 }
 
 func (c *ConfigMap) Get() (*v1.ConfigMap, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
 	return c.client.CoreV1().ConfigMaps(c.Namespace).Get(c.ctx, c.Name, metav1.GetOptions{})
 }
 
 func (c *ConfigMap) CreateOrUpdate() error {
+	if c.err != nil {
+		return c.err
+	}
 	_, err := c.client.CoreV1().ConfigMaps(c.Namespace).Get(c.ctx, c.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -123,6 +145,9 @@ func (c *ConfigMap) Empty() bool {
 }
 
 func (c *ConfigMap) Equal(keys []string) bool {
+	if c.err != nil {
+		return false
+	}
 	cm, err := c.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
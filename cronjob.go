@@ -0,0 +1,283 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/12 11:03:49
+ Desc     : cronjob
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+type CronJob struct {
+	*LinkInfo
+	*batchv1.CronJob
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewCronJob(ctx context.Context) *CronJob {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &CronJob{
+		CronJob: &batchv1.CronJob{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "CronJob",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       batchv1.CronJobSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (c *CronJob) Link(region, config string, opts ...ClientOption) *CronJob {
+	c.Region = region
+	c.Config = config
+	c.client, c.err = NewKubeClient(region, config, opts...)
+	return c
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (c *CronJob) LinkClient(client kubernetes.Interface) *CronJob {
+	c.client, c.err = NewKubeClientFor(client), nil
+	return c
+}
+
+func (c *CronJob) Metadata(name, namespace string) *CronJob {
+	c.Name, c.Namespace = name, namespace
+	return c
+}
+
+func (c *CronJob) Labels(labels map[string]string) *CronJob {
+	if c.CronJob.Labels == nil {
+		c.CronJob.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		c.CronJob.Labels[k] = v
+	}
+	return c
+}
+
+func (c *CronJob) Annotations(annotations map[string]string) *CronJob {
+	if c.CronJob.Annotations == nil {
+		c.CronJob.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		c.CronJob.Annotations[k] = v
+	}
+	return c
+}
+
+// Schedule sets the schedule in cron format, e.g. "*/5 * * * *".
+func (c *CronJob) Schedule(schedule string) *CronJob {
+	c.CronJob.Spec.Schedule = schedule
+	return c
+}
+
+// TimeZone sets the time zone of the schedule, e.g. "Asia/Shanghai".
+func (c *CronJob) TimeZone(timeZone string) *CronJob {
+	c.CronJob.Spec.TimeZone = &timeZone
+	return c
+}
+
+func (c *CronJob) ConcurrencyPolicy(policy batchv1.ConcurrencyPolicy) *CronJob {
+	c.CronJob.Spec.ConcurrencyPolicy = policy
+	return c
+}
+
+func (c *CronJob) StartingDeadlineSeconds(seconds int64) *CronJob {
+	c.CronJob.Spec.StartingDeadlineSeconds = &seconds
+	return c
+}
+
+func (c *CronJob) SuccessfulJobsHistoryLimit(limit int32) *CronJob {
+	c.CronJob.Spec.SuccessfulJobsHistoryLimit = &limit
+	return c
+}
+
+func (c *CronJob) FailedJobsHistoryLimit(limit int32) *CronJob {
+	c.CronJob.Spec.FailedJobsHistoryLimit = &limit
+	return c
+}
+
+// Template sets the pod template of the jobs, the restart policy defaults
+// to Never.
+func (c *CronJob) Template(pod *PodTemplate) *CronJob {
+	if pod == nil {
+		return c
+	}
+	c.CronJob.Spec.JobTemplate.Spec.Template = pod.Template
+	if c.CronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy == "" {
+		c.CronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy = v1.RestartPolicyNever
+	}
+	return c
+}
+
+// JobTemplate takes the spec, labels and annotations of the job builder as
+// the job template.
+func (c *CronJob) JobTemplate(job *Job) *CronJob {
+	if job == nil {
+		return c
+	}
+	c.CronJob.Spec.JobTemplate = batchv1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      job.Job.Labels,
+			Annotations: job.Job.Annotations,
+		},
+		Spec: *job.Job.Spec.DeepCopy(),
+	}
+	return c
+}
+
+func (c *CronJob) Create() error {
+	if c.err != nil {
+		return c.err
+	}
+	_, err := c.client.BatchV1().CronJobs(c.Namespace).
+		Create(c.ctx, c.CronJob, metav1.CreateOptions{})
+	return err
+}
+
+// Delete removes the cronjob together with its jobs.
+func (c *CronJob) Delete() error {
+	if c.err != nil {
+		return c.err
+	}
+	propagation := metav1.DeletePropagationBackground
+	return c.client.BatchV1().CronJobs(c.Namespace).
+		Delete(c.ctx, c.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
+func (c *CronJob) Update() error {
+	if c.err != nil {
+		return c.err
+	}
+	_, err := c.client.BatchV1().CronJobs(c.Namespace).Update(c.ctx, c.CronJob, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *CronJob) Get() (*batchv1.CronJob, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.client.BatchV1().CronJobs(c.Namespace).Get(c.ctx, c.Name, metav1.GetOptions{})
+}
+
+func (c *CronJob) Empty() bool {
+	if c.err != nil {
+		return false
+	}
+	_, err := c.client.BatchV1().CronJobs(c.Namespace).Get(c.ctx, c.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (c *CronJob) CreateOrUpdate() error {
+	if c.err != nil {
+		return c.err
+	}
+	_, err := c.client.BatchV1().CronJobs(c.Namespace).Get(c.ctx, c.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.Create()
+		}
+		return err
+	}
+	return c.Update()
+}
+
+// Suspend stops scheduling new jobs, the running jobs are not affected.
+func (c *CronJob) Suspend() error {
+	return c.suspend(true)
+}
+
+// Resume schedules the jobs again after Suspend.
+func (c *CronJob) Resume() error {
+	return c.suspend(false)
+}
+
+func (c *CronJob) suspend(suspend bool) error {
+	if c.err != nil {
+		return c.err
+	}
+	data := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	_, err := c.client.BatchV1().CronJobs(c.Namespace).Patch(c.ctx, c.Name,
+		types.MergePatchType, []byte(data), metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	c.CronJob.Spec.Suspend = &suspend
+	return nil
+}
+
+// TriggerNow creates a job from the job template of the cronjob in the
+// cluster like `kubectl create job --from=cronjob/<name>`, the job name is
+// generated when name is empty.
+func (c *CronJob) TriggerNow(name string) (*batchv1.Job, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	cronJob, err := c.Get()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s-manual-%s", cronJob.Name, rand.String(5))
+	}
+	annotations := map[string]string{
+		"cronjob.kubernetes.io/instantiate": "manual",
+	}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+	return c.client.BatchV1().Jobs(cronJob.Namespace).Create(c.ctx, job, metav1.CreateOptions{})
+}
+
+func (c *CronJob) Equal(keys []string) bool {
+	if c.err != nil {
+		return false
+	}
+	cronJob, err := c.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(c.CronJob, cronJob, keys)
+}
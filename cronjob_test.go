@@ -0,0 +1,75 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/12 14:37:20
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCronJobSuspendAndTrigger(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	container := NewContainer(nil).Metadata("report").Image("busybox")
+	job := NewJob(nil).Labels(map[string]string{"app": "report"}).
+		Template(NewPodTemplate(nil).Container(*container)).BackoffLimit(1)
+	cronJob := NewCronJob(nil).LinkClient(client).Metadata("report", "default").
+		Schedule("0 * * * *").TimeZone("Asia/Shanghai").
+		ConcurrencyPolicy(batchv1.ForbidConcurrent).JobTemplate(job)
+	if err := cronJob.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cronJob.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cronJob.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Suspend == nil || !*got.Spec.Suspend {
+		t.Error("expect cronjob suspended")
+	}
+	if err := cronJob.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = cronJob.Get(); *got.Spec.Suspend {
+		t.Error("expect cronjob resumed")
+	}
+
+	triggered, err := cronJob.TriggerNow("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggered.OwnerReferences) != 1 || triggered.OwnerReferences[0].Kind != "CronJob" || triggered.OwnerReferences[0].Name != "report" {
+		t.Errorf("unexpected owner references %v", triggered.OwnerReferences)
+	}
+	if triggered.Annotations["cronjob.kubernetes.io/instantiate"] != "manual" {
+		t.Errorf("unexpected annotations %v", triggered.Annotations)
+	}
+	if triggered.Labels["app"] != "report" || *triggered.Spec.BackoffLimit != 1 {
+		t.Error("expect the job built from the job template")
+	}
+}
+
+func TestCronJobEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cronJob := NewCronJob(nil).LinkClient(client).Metadata("report", "default").Schedule("* * * * *")
+	if err := cronJob.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !cronJob.Equal(nil) {
+		t.Error("expect equal to the created cronjob")
+	}
+	other := NewCronJob(nil).LinkClient(client).Metadata("report", "default").Schedule("0 0 * * *")
+	if other.Equal(nil) {
+		t.Error("expect not equal with another schedule")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type DaemonSet struct {
@@ -23,6 +24,8 @@ type DaemonSet struct {
 	*appsv1.DaemonSet
 	client *KubeClient
 	ctx    context.Context
+	// readyInterval rechecks WaitForReady without an event
+	readyInterval time.Duration
 }
 
 func NewDaemonSet(ctx context.Context) *DaemonSet {
@@ -44,10 +47,16 @@ func NewDaemonSet(ctx context.Context) *DaemonSet {
 	}
 }
 
-func (d *DaemonSet) Link(region, config string) *DaemonSet {
+func (d *DaemonSet) Link(region, config string, opts ...ClientOption) *DaemonSet {
 	d.Region = region
 	d.Config = config
-	d.client = NewKubeClient(region, config)
+	d.client, d.err = NewKubeClient(region, config, opts...)
+	return d
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (d *DaemonSet) LinkClient(client kubernetes.Interface) *DaemonSet {
+	d.client, d.err = NewKubeClientFor(client), nil
 	return d
 }
 
@@ -95,28 +104,43 @@ func (d *DaemonSet) Template(pt *PodTemplate) *DaemonSet {
 }
 
 func (d *DaemonSet) Create() error {
+	if d.err != nil {
+		return d.err
+	}
 	daemonsets := d.client.AppsV1().DaemonSets(d.Namespace)
 	_, err := daemonsets.Create(d.ctx, d.DaemonSet, metav1.CreateOptions{})
 	return err
 }
 
 func (d *DaemonSet) Delete() error {
+	if d.err != nil {
+		return d.err
+	}
 	daemonsets := d.client.AppsV1().DaemonSets(d.Namespace)
 	return daemonsets.Delete(d.ctx, d.Name, metav1.DeleteOptions{})
 }
 
 func (d *DaemonSet) Update() error {
+	if d.err != nil {
+		return d.err
+	}
 	daemonsets := d.client.AppsV1().DaemonSets(d.Namespace)
 	_, err := daemonsets.Update(d.ctx, d.DaemonSet, metav1.UpdateOptions{})
 	return err
 }
 
 func (d *DaemonSet) Get() (*v1.DaemonSet, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
 	return d.client.AppsV1().DaemonSets(d.Namespace).
 		Get(d.ctx, d.Name, metav1.GetOptions{})
 }
 
 func (d *DaemonSet) CreateOrUpdate() error {
+	if d.err != nil {
+		return d.err
+	}
 	_, err := d.client.AppsV1().DaemonSets(d.Namespace).Get(d.ctx, d.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -128,12 +152,18 @@ func (d *DaemonSet) CreateOrUpdate() error {
 }
 
 func (d *DaemonSet) Empty() bool {
+	if d.err != nil {
+		return false
+	}
 	_, err := d.client.AppsV1().DaemonSets(d.Namespace).Get(d.ctx, d.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 // implement the rollout method
 func (d *DaemonSet) Rollout() error {
+	if d.err != nil {
+		return d.err
+	}
 	if d.DaemonSet.Annotations == nil {
 		d.DaemonSet.Annotations = make(map[string]string)
 	}
@@ -142,7 +172,90 @@ func (d *DaemonSet) Rollout() error {
 	return err
 }
 
+// ReadyCheckInterval sets how often WaitForReady rechecks the daemonset without
+// an event, it defaults to 2s.
+func (d *DaemonSet) ReadyCheckInterval(interval time.Duration) *DaemonSet {
+	d.readyInterval = interval
+	return d
+}
+
+// WaitForReady waits until the latest spec is observed and the pods on all
+// scheduled nodes are updated and available, it fails fast with a
+// *ReadyError when a pod of the current revision is in CrashLoopBackOff or
+// ImagePullBackOff. A nil ctx uses the context of the builder.
+func (d *DaemonSet) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	if d.err != nil {
+		return d.err
+	}
+	if ctx == nil {
+		ctx = d.ctx
+	}
+	daemonSets := d.client.AppsV1().DaemonSets(d.Namespace)
+	return waitForReady(ctx, timeout, d.readyInterval, "DaemonSet", d.Namespace, d.Name, daemonSets.Watch,
+		func(ctx context.Context) (bool, error) {
+			ds, err := daemonSets.Get(ctx, d.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if ds.Generation > ds.Status.ObservedGeneration {
+				return false, nil
+			}
+			status := ds.Status
+			if status.UpdatedNumberScheduled == status.DesiredNumberScheduled && status.NumberAvailable == status.DesiredNumberScheduled {
+				return true, nil
+			}
+			// only the pods of the current revision count, the old ones may
+			// still crash while they are being replaced
+			hash, err := d.currentRevisionHash(ctx, ds)
+			if err != nil || hash == "" {
+				return false, err
+			}
+			selector := ds.Spec.Selector.DeepCopy()
+			if selector.MatchLabels == nil {
+				selector.MatchLabels = make(map[string]string)
+			}
+			selector.MatchLabels[appsv1.ControllerRevisionHashLabelKey] = hash
+			pods, err := selectPods(ctx, d.client, d.Namespace, selector)
+			if err != nil {
+				return false, err
+			}
+			return false, podsFailure("DaemonSet", d.Namespace, d.Name, pods)
+		})
+}
+
+// currentRevisionHash returns the hash of the controller revision with the
+// highest revision, or "" before the controller created it.
+func (d *DaemonSet) currentRevisionHash(ctx context.Context, ds *appsv1.DaemonSet) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	list, err := d.client.AppsV1().ControllerRevisions(d.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+	var current *appsv1.ControllerRevision
+	for i := range list.Items {
+		revision := &list.Items[i]
+		if !metav1.IsControlledBy(revision, ds) {
+			continue
+		}
+		if current == nil || revision.Revision > current.Revision {
+			current = revision
+		}
+	}
+	if current == nil {
+		return "", nil
+	}
+	return current.Labels[appsv1.ControllerRevisionHashLabelKey], nil
+}
+
 func (d *DaemonSet) Equal(keys []string) bool {
+	if d.err != nil {
+		return false
+	}
 	daemonSet, err := d.Get()
 	if err != nil && !errors.IsNotFound(err) {
 	}
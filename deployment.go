@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 )
 
 type Deployment struct {
@@ -32,6 +33,8 @@ type Deployment struct {
 	*appsv1.Deployment
 	ctx    context.Context
 	client *KubeClient
+	// readyInterval rechecks WaitForReady without an event
+	readyInterval time.Duration
 }
 
 func NewDeployment(ctx context.Context) *Deployment {
@@ -53,10 +56,16 @@ func NewDeployment(ctx context.Context) *Deployment {
 	}
 }
 
-func (d *Deployment) Link(region, config string) *Deployment {
+func (d *Deployment) Link(region, config string, opts ...ClientOption) *Deployment {
 	d.Region = region
 	d.Config = config
-	d.client = NewKubeClient(region, config)
+	d.client, d.err = NewKubeClient(region, config, opts...)
+	return d
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (d *Deployment) LinkClient(client kubernetes.Interface) *Deployment {
+	d.client, d.err = NewKubeClientFor(client), nil
 	return d
 }
 
@@ -107,32 +116,50 @@ func (d *Deployment) Strategy(strategy appsv1.DeploymentStrategy) *Deployment {
 }
 
 func (d *Deployment) Create() error {
+	if d.err != nil {
+		return d.err
+	}
 	_, err := d.client.AppsV1().Deployments(d.Namespace).
 		Create(d.ctx, d.Deployment, metav1.CreateOptions{})
 	return err
 }
 
 func (d *Deployment) Delete() error {
+	if d.err != nil {
+		return d.err
+	}
 	return d.client.AppsV1().Deployments(d.Namespace).
 		Delete(d.ctx, d.Name, metav1.DeleteOptions{})
 }
 
 func (d *Deployment) Update() error {
+	if d.err != nil {
+		return d.err
+	}
 	_, err := d.client.AppsV1().Deployments(d.Namespace).Update(d.ctx, d.Deployment, metav1.UpdateOptions{})
 	return err
 }
 
 func (d *Deployment) Get() (*appsv1.Deployment, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
 	return d.client.AppsV1().Deployments(d.Namespace).Get(d.ctx, d.Name, metav1.GetOptions{})
 }
 
 func (d *Deployment) Empty() bool {
+	if d.err != nil {
+		return false
+	}
 	_, err := d.client.AppsV1().Deployments(d.Namespace).
 		Get(d.ctx, d.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (d *Deployment) CreateOrUpdate() error {
+	if d.err != nil {
+		return d.err
+	}
 	_, err := d.client.AppsV1().Deployments(d.Namespace).Get(d.ctx, d.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -145,6 +172,9 @@ func (d *Deployment) CreateOrUpdate() error {
 
 // implement the rollout method
 func (d *Deployment) Rollout() error {
+	if d.err != nil {
+		return d.err
+	}
 	if d.Deployment.Annotations == nil {
 		d.Deployment.Annotations = make(map[string]string)
 	}
@@ -154,6 +184,88 @@ func (d *Deployment) Rollout() error {
 	return err
 }
 
+// WithPDB creates or updates the pod disruption budget with the selector of
+// the deployment, so that an eviction never takes down all the replicas at
+// once.
+func (d *Deployment) WithPDB(pdb *PodDisruptionBudget) error {
+	if d.err != nil {
+		return d.err
+	}
+	if pdb == nil {
+		return nil
+	}
+	if d.Deployment.Spec.Selector == nil {
+		return fmt.Errorf("deployment %s has no selector", d.Name)
+	}
+	return applyWorkloadPDB(d.client, pdb, d.Name, d.Namespace, d.Deployment.Spec.Selector)
+}
+
+// ReadyCheckInterval sets how often WaitForReady rechecks the deployment without
+// an event, it defaults to 2s.
+func (d *Deployment) ReadyCheckInterval(interval time.Duration) *Deployment {
+	d.readyInterval = interval
+	return d
+}
+
+// WaitForReady waits until the latest spec is observed and all replicas are
+// updated and available, it fails fast with a *ReadyError when the progress
+// deadline is exceeded or a pod of the new replicaset is in CrashLoopBackOff
+// or ImagePullBackOff. A nil ctx uses the context of the builder.
+func (d *Deployment) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	if d.err != nil {
+		return d.err
+	}
+	if ctx == nil {
+		ctx = d.ctx
+	}
+	deployments := d.client.AppsV1().Deployments(d.Namespace)
+	return waitForReady(ctx, timeout, d.readyInterval, "Deployment", d.Namespace, d.Name, deployments.Watch,
+		func(ctx context.Context) (bool, error) {
+			deploy, err := deployments.Get(ctx, d.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if deploy.Generation > deploy.Status.ObservedGeneration {
+				return false, nil
+			}
+			for _, cond := range deploy.Status.Conditions {
+				if cond.Type == appsv1.DeploymentProgressing && cond.Reason == ReasonProgressDeadlineExceeded {
+					return false, &ReadyError{
+						Kind:      "Deployment",
+						Namespace: d.Namespace,
+						Name:      d.Name,
+						Reason:    ReasonProgressDeadlineExceeded,
+						Message:   cond.Message,
+					}
+				}
+			}
+			replicas := int32(1)
+			if deploy.Spec.Replicas != nil {
+				replicas = *deploy.Spec.Replicas
+			}
+			status := deploy.Status
+			if status.UpdatedReplicas == replicas && status.Replicas == replicas && status.AvailableReplicas == replicas {
+				return true, nil
+			}
+			// only the pods of the new replicaset count, the old ones may
+			// still crash while they are being replaced
+			rs, err := d.newReplicaSet(ctx, deploy)
+			if err != nil || rs == nil {
+				return false, err
+			}
+			selector := deploy.Spec.Selector.DeepCopy()
+			if selector.MatchLabels == nil {
+				selector.MatchLabels = make(map[string]string)
+			}
+			selector.MatchLabels[appsv1.DefaultDeploymentUniqueLabelKey] = rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+			pods, err := selectPods(ctx, d.client, d.Namespace, selector)
+			if err != nil {
+				return false, err
+			}
+			return false, podsFailure("Deployment", d.Namespace, d.Name, pods)
+		})
+}
+
 func (d *Deployment) GetReplicas() (int32, error) {
 	deploy, err := d.Get()
 	if err != nil {
@@ -162,7 +274,47 @@ func (d *Deployment) GetReplicas() (int32, error) {
 	return *deploy.Spec.Replicas, nil
 }
 
+// Scale sets the replicas through the scale subresource, the rest of the
+// deployment is left untouched.
+func (d *Deployment) Scale(replicas int32) error {
+	if d.err != nil {
+		return d.err
+	}
+	if err := scaleTo(d.ctx, d.client.AppsV1().Deployments(d.Namespace), d.Name, replicas); err != nil {
+		return err
+	}
+	d.Deployment.Spec.Replicas = &replicas
+	return nil
+}
+
+// Hibernate scales the deployment to zero, the replicas are recorded in the
+// HibernatedReplicasAnnotation for Wake.
+func (d *Deployment) Hibernate() error {
+	if d.err != nil {
+		return d.err
+	}
+	return hibernate(d.ctx, d.client.AppsV1().Deployments(d.Namespace), d.Name, d.patch)
+}
+
+// Wake restores the replicas recorded by Hibernate.
+func (d *Deployment) Wake() error {
+	deploy, err := d.Get()
+	if err != nil {
+		return err
+	}
+	return wake(d.ctx, d.client.AppsV1().Deployments(d.Namespace), d.Name, deploy.Annotations, d.patch)
+}
+
+func (d *Deployment) patch(data []byte) error {
+	_, err := d.client.AppsV1().Deployments(d.Namespace).Patch(d.ctx, d.Name,
+		types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
 func (d *Deployment) Equal(keys []string) bool {
+	if d.err != nil {
+		return false
+	}
 	deployment, err := d.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type Endpoint struct {
@@ -42,10 +43,16 @@ func NewEndpoint(ctx context.Context) *Endpoint {
 	}
 }
 
-func (e *Endpoint) Link(region, config string) *Endpoint {
+func (e *Endpoint) Link(region, config string, opts ...ClientOption) *Endpoint {
 	e.Region = region
 	e.Config = config
-	e.client = NewKubeClient(region, config)
+	e.client, e.err = NewKubeClient(region, config, opts...)
+	return e
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (e *Endpoint) LinkClient(client kubernetes.Interface) *Endpoint {
+	e.client, e.err = NewKubeClientFor(client), nil
 	return e
 }
 
@@ -90,25 +97,40 @@ func (e *Endpoint) Port(name string, port int32, protocol v1.Protocol) *Endpoint
 }
 
 func (e *Endpoint) Get() (*v1.Endpoints, error) {
-	return e.client.Clientset.CoreV1().Endpoints(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.client.CoreV1().Endpoints(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
 }
 
 func (e *Endpoint) Create() error {
-	_, err := e.client.Clientset.CoreV1().Endpoints(e.Namespace).Create(e.ctx, e.Endpoints, metav1.CreateOptions{})
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.CoreV1().Endpoints(e.Namespace).Create(e.ctx, e.Endpoints, metav1.CreateOptions{})
 	return err
 }
 
 func (e *Endpoint) Delete() error {
-	return e.client.Clientset.CoreV1().Endpoints(e.Namespace).Delete(e.ctx, e.Name, metav1.DeleteOptions{})
+	if e.err != nil {
+		return e.err
+	}
+	return e.client.CoreV1().Endpoints(e.Namespace).Delete(e.ctx, e.Name, metav1.DeleteOptions{})
 }
 
 func (e *Endpoint) Update() error {
-	_, err := e.client.Clientset.CoreV1().Endpoints(e.Namespace).Update(e.ctx, e.Endpoints, metav1.UpdateOptions{})
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.CoreV1().Endpoints(e.Namespace).Update(e.ctx, e.Endpoints, metav1.UpdateOptions{})
 	return err
 }
 
 func (e *Endpoint) CreateOrUpdate() error {
-	_, err := e.client.Clientset.CoreV1().Endpoints(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.CoreV1().Endpoints(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return e.Create()
@@ -0,0 +1,248 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/27 10:36:14
+ Desc     : endpoint slice
+*/
+
+package kube
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type EndpointSlice struct {
+	*LinkInfo
+	*discoveryv1.EndpointSlice
+	client *KubeClient
+	ctx    context.Context
+}
+
+func NewEndpointSlice(ctx context.Context) *EndpointSlice {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &EndpointSlice{
+		EndpointSlice: &discoveryv1.EndpointSlice{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "EndpointSlice",
+				APIVersion: "discovery.k8s.io/v1",
+			},
+			ObjectMeta:  metav1.ObjectMeta{},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (e *EndpointSlice) Link(region, config string, opts ...ClientOption) *EndpointSlice {
+	e.Region = region
+	e.Config = config
+	e.client, e.err = NewKubeClient(region, config, opts...)
+	return e
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (e *EndpointSlice) LinkClient(client kubernetes.Interface) *EndpointSlice {
+	e.client, e.err = NewKubeClientFor(client), nil
+	return e
+}
+
+func (e *EndpointSlice) Metadata(name, namespace string) *EndpointSlice {
+	e.Name, e.Namespace = name, namespace
+	return e
+}
+
+func (e *EndpointSlice) Labels(labels map[string]string) *EndpointSlice {
+	if e.EndpointSlice.Labels == nil {
+		e.EndpointSlice.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		e.EndpointSlice.Labels[k] = v
+	}
+	return e
+}
+
+// Service assigns the slice to the service by the kubernetes.io/service-name
+// label.
+func (e *EndpointSlice) Service(serviceName string) *EndpointSlice {
+	return e.Labels(map[string]string{discoveryv1.LabelServiceName: serviceName})
+}
+
+// AddressType sets the type of all addresses in the slice, it defaults to
+// IPv4.
+func (e *EndpointSlice) AddressType(addressType discoveryv1.AddressType) *EndpointSlice {
+	e.EndpointSlice.AddressType = addressType
+	return e
+}
+
+// Endpoint adds an endpoint, the addresses are treated as fungible and only
+// the first one is used by kube-proxy.
+func (e *EndpointSlice) Endpoint(addresses []string, nodeName, zone string, conditions discoveryv1.EndpointConditions) *EndpointSlice {
+	endpoint := discoveryv1.Endpoint{
+		Addresses:  addresses,
+		Conditions: conditions,
+	}
+	if nodeName != "" {
+		endpoint.NodeName = &nodeName
+	}
+	if zone != "" {
+		endpoint.Zone = &zone
+	}
+	e.EndpointSlice.Endpoints = append(e.EndpointSlice.Endpoints, endpoint)
+	return e
+}
+
+func (e *EndpointSlice) Port(name string, port int32, protocol v1.Protocol) *EndpointSlice {
+	e.EndpointSlice.Ports = append(e.EndpointSlice.Ports, discoveryv1.EndpointPort{
+		Name:     &name,
+		Port:     &port,
+		Protocol: &protocol,
+	})
+	return e
+}
+
+func (e *EndpointSlice) Get() (*discoveryv1.EndpointSlice, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.client.DiscoveryV1().EndpointSlices(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
+}
+
+// List returns all slices of the service, see Service.
+func (e *EndpointSlice) List() (*discoveryv1.EndpointSliceList, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.client.DiscoveryV1().EndpointSlices(e.Namespace).List(e.ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + e.serviceName(),
+	})
+}
+
+func (e *EndpointSlice) Create() error {
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.DiscoveryV1().EndpointSlices(e.Namespace).Create(e.ctx, e.EndpointSlice, metav1.CreateOptions{})
+	return err
+}
+
+func (e *EndpointSlice) Delete() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.client.DiscoveryV1().EndpointSlices(e.Namespace).Delete(e.ctx, e.Name, metav1.DeleteOptions{})
+}
+
+func (e *EndpointSlice) Update() error {
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.DiscoveryV1().EndpointSlices(e.Namespace).Update(e.ctx, e.EndpointSlice, metav1.UpdateOptions{})
+	return err
+}
+
+func (e *EndpointSlice) Empty() bool {
+	if e.err != nil {
+		return false
+	}
+	_, err := e.client.DiscoveryV1().EndpointSlices(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (e *EndpointSlice) CreateOrUpdate() error {
+	if e.err != nil {
+		return e.err
+	}
+	_, err := e.client.DiscoveryV1().EndpointSlices(e.Namespace).Get(e.ctx, e.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return e.Create()
+		}
+		return err
+	}
+	return e.Update()
+}
+
+func (e *EndpointSlice) Equal(keys []string) bool {
+	if e.err != nil {
+		return false
+	}
+	slice, err := e.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"AddressType", "Endpoints", "Ports"}
+	}
+	return ResourceEqual(e.EndpointSlice, slice, keys)
+}
+
+// Addresses returns the endpoints of all slices of the service.
+func (e *EndpointSlice) Addresses() ([]discoveryv1.Endpoint, error) {
+	slices, err := e.List()
+	if err != nil {
+		return nil, err
+	}
+	endpoints := make([]discoveryv1.Endpoint, 0)
+	for _, slice := range slices.Items {
+		endpoints = append(endpoints, slice.Endpoints...)
+	}
+	return endpoints, nil
+}
+
+// Ports returns the ports of all slices of the service, the ports repeated
+// by the slices are returned once.
+func (e *EndpointSlice) Ports() ([]discoveryv1.EndpointPort, error) {
+	slices, err := e.List()
+	if err != nil {
+		return nil, err
+	}
+	type portKey struct {
+		name     string
+		port     int32
+		protocol v1.Protocol
+	}
+	seen := make(map[portKey]bool)
+	ports := make([]discoveryv1.EndpointPort, 0)
+	for _, slice := range slices.Items {
+		for _, port := range slice.Ports {
+			key := portKey{}
+			if port.Name != nil {
+				key.name = *port.Name
+			}
+			if port.Port != nil {
+				key.port = *port.Port
+			}
+			if port.Protocol != nil {
+				key.protocol = *port.Protocol
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// serviceName prefers the service label over the name of the slice.
+func (e *EndpointSlice) serviceName() string {
+	if name, ok := e.EndpointSlice.Labels[discoveryv1.LabelServiceName]; ok {
+		return name
+	}
+	return e.Name
+}
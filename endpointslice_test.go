@@ -0,0 +1,56 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/27 15:02:45
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEndpointSliceAggregate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ready := true
+	for name, address := range map[string]string{"inference-a": "10.0.0.1", "inference-b": "10.0.0.2"} {
+		err := NewEndpointSlice(nil).LinkClient(client).Metadata(name, "default").Service("inference").
+			Endpoint([]string{address}, "gpu-node-1", "zone-a", discoveryv1.EndpointConditions{Ready: &ready}).
+			Port("http", 8080, v1.ProtocolTCP).
+			Port("grpc", 9090, v1.ProtocolTCP).
+			Create()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a slice of another service is not aggregated
+	if err := NewEndpointSlice(nil).LinkClient(client).Metadata("other-a", "default").Service("other").
+		Endpoint([]string{"10.0.0.3"}, "", "", discoveryv1.EndpointConditions{}).Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	slices := NewEndpointSlice(nil).LinkClient(client).Metadata("inference", "default")
+	endpoints, err := slices.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expect 2 endpoints, got %v", endpoints)
+	}
+	if *endpoints[0].NodeName != "gpu-node-1" || *endpoints[0].Zone != "zone-a" || !*endpoints[0].Conditions.Ready {
+		t.Errorf("unexpected endpoint %v", endpoints[0])
+	}
+	ports, err := slices.Ports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 2 {
+		t.Errorf("expect the ports of the slices merged, got %v", ports)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/05 10:17:26
+ Desc     : exec commands in the containers of a pod
+*/
+
+package kube
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// ExecOption configures the streams of Exec.
+type ExecOption func(*remotecommand.StreamOptions)
+
+// WithTerminalSizeQueue resizes the terminal of a tty session with the sizes
+// of the queue, e.g. on SIGWINCH of the local terminal.
+func WithTerminalSizeQueue(queue remotecommand.TerminalSizeQueue) ExecOption {
+	return func(o *remotecommand.StreamOptions) {
+		o.TerminalSizeQueue = queue
+	}
+}
+
+// Exec runs the command in the container until it exits. The streams are
+// connected by WebSocket, or by SPDY when the api server does not support
+// it. With tty stderr is merged into stdout by the terminal, so stderr is
+// not used. A non-zero exit code is returned as an exec.ExitError.
+func (p *Pod) Exec(container string, command []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, opts ...ExecOption) error {
+	if p.err != nil {
+		return p.err
+	}
+	config := p.client.RESTConfig()
+	if config == nil {
+		return fmt.Errorf("client of pod %s has no rest config, set it by LinkConfig", p.Name)
+	}
+	if tty {
+		stderr = nil
+	}
+	config = rest.CopyConfig(config)
+	config.APIPath = "/api"
+	config.GroupVersion = &v1.SchemeGroupVersion
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	restClient, err := rest.RESTClientFor(config)
+	if err != nil {
+		return err
+	}
+	url := restClient.Post().Namespace(p.Namespace).Resource("pods").Name(p.Name).SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil,
+			TTY:       tty,
+		}, scheme.ParameterCodec).URL()
+	websocketExecutor, err := remotecommand.NewWebSocketExecutor(config, "GET", url.String())
+	if err != nil {
+		return err
+	}
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(config, "POST", url)
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExecutor, spdyExecutor, httpstream.IsUpgradeFailure)
+	if err != nil {
+		return err
+	}
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+		Tty:    tty,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&streamOptions)
+		}
+	}
+	return executor.StreamWithContext(p.ctx, streamOptions)
+}
+
+// ExecOutput runs the command in the container and returns its output, a
+// non-zero exit code is not an error.
+func (p *Pod) ExecOutput(container string, command []string) (stdout, stderr string, exitCode int, err error) {
+	var outBuf, errBuf bytes.Buffer
+	err = p.Exec(container, command, nil, &outBuf, &errBuf, false)
+	var exitErr exec.ExitError
+	if errors.As(err, &exitErr) {
+		return outBuf.String(), errBuf.String(), exitErr.ExitStatus(), nil
+	}
+	return outBuf.String(), errBuf.String(), 0, err
+}
@@ -0,0 +1,198 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/05 15:48:12
+ Desc     :
+*/
+
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// execServer stands in for the exec endpoint of the kubelet, it serves the
+// v4 SPDY protocol and runs the commands "echo", "cat" and "fail".
+func execServer(t *testing.T, sizes chan<- remotecommand.TerminalSize) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			http.Error(w, "websocket is not supported", http.StatusBadRequest)
+			return
+		}
+		if !strings.HasSuffix(req.URL.Path, "/namespaces/default/pods/trainer-0/exec") {
+			http.Error(w, "unexpected path "+req.URL.Path, http.StatusNotFound)
+			return
+		}
+		if _, err := httpstream.Handshake(req, w, []string{remotecommandconsts.StreamProtocolV4Name}); err != nil {
+			return
+		}
+		query := req.URL.Query()
+		expected := 1
+		for _, name := range []string{"stdin", "stdout", "stderr", "tty"} {
+			if query.Get(name) == "true" {
+				expected++
+			}
+		}
+		received := make(chan httpstream.Stream, expected)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(stream httpstream.Stream, replySent <-chan struct{}) error {
+			received <- stream
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		streams := make(map[string]httpstream.Stream)
+		for len(streams) < expected {
+			select {
+			case stream := <-received:
+				streams[stream.Headers().Get(v1.StreamType)] = stream
+			case <-time.After(5 * time.Second):
+				t.Errorf("expect %d streams, got %d", expected, len(streams))
+				return
+			}
+		}
+		// resized is closed once a size arrived or the resize stream closed,
+		// the status must not be written before as it ends the session
+		resized := make(chan struct{})
+		if resize, ok := streams[v1.StreamTypeResize]; ok {
+			go func() {
+				defer close(resized)
+				var size remotecommand.TerminalSize
+				if err := json.NewDecoder(resize).Decode(&size); err == nil {
+					sizes <- size
+				}
+			}()
+		} else {
+			close(resized)
+		}
+
+		stdout, stderr := streams[v1.StreamTypeStdout], streams[v1.StreamTypeStderr]
+		command := query["command"]
+		exitCode := 0
+		switch command[0] {
+		case "echo":
+			fmt.Fprintln(stdout, strings.Join(command[1:], " "))
+		case "cat":
+			io.Copy(stdout, streams[v1.StreamTypeStdin])
+		case "fail":
+			fmt.Fprintln(stderr, "disk check failed")
+			exitCode = 3
+		}
+		for _, stream := range []httpstream.Stream{stdout, stderr} {
+			if stream != nil {
+				stream.Close()
+			}
+		}
+		status := metav1.Status{Status: metav1.StatusSuccess}
+		if exitCode != 0 {
+			status = metav1.Status{
+				Status: metav1.StatusFailure,
+				Reason: remotecommandconsts.NonZeroExitCodeReason,
+				Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+					{Type: remotecommandconsts.ExitCodeCauseType, Message: fmt.Sprint(exitCode)},
+				}},
+			}
+		}
+		<-resized
+		errStream := streams[v1.StreamTypeError]
+		json.NewEncoder(errStream).Encode(status)
+		errStream.Close()
+	}))
+}
+
+type sizeQueue chan remotecommand.TerminalSize
+
+func (q sizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+func TestPodExec(t *testing.T) {
+	sizes := make(chan remotecommand.TerminalSize, 1)
+	server := execServer(t, sizes)
+	defer server.Close()
+	pod := NewPod(nil).LinkClient(fake.NewSimpleClientset()).Metadata("trainer-0", "default")
+	if err := pod.Exec("trainer", []string{"echo"}, nil, io.Discard, nil, false); err == nil {
+		t.Fatal("expect error without rest config")
+	}
+	pod.LinkConfig(&rest.Config{Host: server.URL})
+	if err := pod.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, exitCode, err := pod.ExecOutput("trainer", []string{"echo", "hello", "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "hello world\n" || stderr != "" || exitCode != 0 {
+		t.Fatalf("unexpected output %q %q %d", stdout, stderr, exitCode)
+	}
+
+	stdout, stderr, exitCode, err = pod.ExecOutput("trainer", []string{"fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "" || stderr != "disk check failed\n" || exitCode != 3 {
+		t.Fatalf("unexpected output %q %q %d", stdout, stderr, exitCode)
+	}
+
+	// stdin with a tty which is resized
+	queue := make(sizeQueue, 1)
+	queue <- remotecommand.TerminalSize{Width: 120, Height: 40}
+	var out strings.Builder
+	err = pod.Exec("trainer", []string{"cat"}, strings.NewReader("nvidia-smi\n"), &out, io.Discard, true,
+		WithTerminalSizeQueue(queue))
+	close(queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "nvidia-smi\n" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	select {
+	case size := <-sizes:
+		if size.Width != 120 || size.Height != 40 {
+			t.Fatalf("unexpected terminal size %+v", size)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expect the terminal to be resized")
+	}
+}
+
+func TestPodLinkConfig(t *testing.T) {
+	server := execServer(t, make(chan remotecommand.TerminalSize, 1))
+	defer server.Close()
+	// the clientset is built from the config without a linked client
+	pod := NewPod(nil).Metadata("trainer-0", "default").LinkConfig(&rest.Config{Host: server.URL})
+	stdout, _, exitCode, err := pod.ExecOutput("trainer", []string{"echo", "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "hello\n" || exitCode != 0 {
+		t.Fatalf("unexpected output %q %d", stdout, exitCode)
+	}
+	if err := NewPod(nil).LinkConfig(nil).Err(); err == nil {
+		t.Error("expect error of a nil rest config")
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/24 10:27:31
+ Desc     : horizontal pod autoscaler
+*/
+
+package kube
+
+import (
+	"context"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type HPA struct {
+	*LinkInfo
+	*autoscalingv2.HorizontalPodAutoscaler
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewHPA(ctx context.Context) *HPA {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &HPA{
+		HorizontalPodAutoscaler: &autoscalingv2.HorizontalPodAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "HorizontalPodAutoscaler",
+				APIVersion: "autoscaling/v2",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (h *HPA) Link(region, config string, opts ...ClientOption) *HPA {
+	h.Region = region
+	h.Config = config
+	h.client, h.err = NewKubeClient(region, config, opts...)
+	return h
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (h *HPA) LinkClient(client kubernetes.Interface) *HPA {
+	h.client, h.err = NewKubeClientFor(client), nil
+	return h
+}
+
+func (h *HPA) Metadata(name, namespace string) *HPA {
+	h.Name, h.Namespace = name, namespace
+	return h
+}
+
+func (h *HPA) Labels(labels map[string]string) *HPA {
+	if h.HorizontalPodAutoscaler.Labels == nil {
+		h.HorizontalPodAutoscaler.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		h.HorizontalPodAutoscaler.Labels[k] = v
+	}
+	return h
+}
+
+func (h *HPA) Annotations(annotations map[string]string) *HPA {
+	if h.HorizontalPodAutoscaler.Annotations == nil {
+		h.HorizontalPodAutoscaler.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		h.HorizontalPodAutoscaler.Annotations[k] = v
+	}
+	return h
+}
+
+// Target scales the workload of the kind, e.g. apps/v1 Deployment.
+func (h *HPA) Target(apiVersion, kind, name string) *HPA {
+	h.HorizontalPodAutoscaler.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+	}
+	return h
+}
+
+func (h *HPA) TargetDeployment(deployment *Deployment) *HPA {
+	if deployment == nil {
+		return h
+	}
+	return h.Target("apps/v1", "Deployment", deployment.Name)
+}
+
+func (h *HPA) TargetStatefulSet(statefulSet *StatefulSet) *HPA {
+	if statefulSet == nil {
+		return h
+	}
+	return h.Target("apps/v1", "StatefulSet", statefulSet.Name)
+}
+
+func (h *HPA) Replicas(minReplicas, maxReplicas int32) *HPA {
+	h.HorizontalPodAutoscaler.Spec.MinReplicas = &minReplicas
+	h.HorizontalPodAutoscaler.Spec.MaxReplicas = maxReplicas
+	return h
+}
+
+// ResourceUtilization targets the average utilization of the requests of
+// the resource in percent.
+func (h *HPA) ResourceUtilization(name v1.ResourceName, percent int32) *HPA {
+	return h.metric(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &percent,
+			},
+		},
+	})
+}
+
+func (h *HPA) ResourceAverageValue(name v1.ResourceName, value resource.Quantity) *HPA {
+	return h.metric(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &value,
+			},
+		},
+	})
+}
+
+// PodsMetric targets the average value of a custom metric of the pods, e.g.
+// the queue length of an inference server.
+func (h *HPA) PodsMetric(metric string, averageValue resource.Quantity) *HPA {
+	return h.metric(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: metric,
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &averageValue,
+			},
+		},
+	})
+}
+
+// ObjectMetric targets the value of a custom metric describing another
+// object in the namespace, e.g. the requests per second of an ingress.
+func (h *HPA) ObjectMetric(metric, apiVersion, kind, name string, value resource.Quantity) *HPA {
+	return h.metric(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ObjectMetricSourceType,
+		Object: &autoscalingv2.ObjectMetricSource{
+			DescribedObject: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+				Name:       name,
+			},
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: metric,
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:  autoscalingv2.ValueMetricType,
+				Value: &value,
+			},
+		},
+	})
+}
+
+// ExternalMetric targets the average value per pod of a metric from outside
+// the cluster, selected by the labels.
+func (h *HPA) ExternalMetric(metric string, selector map[string]string, averageValue resource.Quantity) *HPA {
+	identifier := autoscalingv2.MetricIdentifier{
+		Name: metric,
+	}
+	if len(selector) > 0 {
+		identifier.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	}
+	return h.metric(autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: identifier,
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &averageValue,
+			},
+		},
+	})
+}
+
+func (h *HPA) metric(metric autoscalingv2.MetricSpec) *HPA {
+	h.HorizontalPodAutoscaler.Spec.Metrics = append(h.HorizontalPodAutoscaler.Spec.Metrics, metric)
+	return h
+}
+
+// ScaleUp sets the scale up behavior, the replicas are changed by the
+// policies chosen by selectPolicy within the stabilization window.
+func (h *HPA) ScaleUp(stabilizationWindowSeconds int32, selectPolicy autoscalingv2.ScalingPolicySelect, policies []autoscalingv2.HPAScalingPolicy) *HPA {
+	if h.HorizontalPodAutoscaler.Spec.Behavior == nil {
+		h.HorizontalPodAutoscaler.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+	}
+	h.HorizontalPodAutoscaler.Spec.Behavior.ScaleUp = scalingRules(stabilizationWindowSeconds, selectPolicy, policies)
+	return h
+}
+
+// ScaleDown sets the scale down behavior like ScaleUp.
+func (h *HPA) ScaleDown(stabilizationWindowSeconds int32, selectPolicy autoscalingv2.ScalingPolicySelect, policies []autoscalingv2.HPAScalingPolicy) *HPA {
+	if h.HorizontalPodAutoscaler.Spec.Behavior == nil {
+		h.HorizontalPodAutoscaler.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+	}
+	h.HorizontalPodAutoscaler.Spec.Behavior.ScaleDown = scalingRules(stabilizationWindowSeconds, selectPolicy, policies)
+	return h
+}
+
+func (h *HPA) Create() error {
+	if h.err != nil {
+		return h.err
+	}
+	_, err := h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Create(h.ctx, h.HorizontalPodAutoscaler, metav1.CreateOptions{})
+	return err
+}
+
+func (h *HPA) Delete() error {
+	if h.err != nil {
+		return h.err
+	}
+	return h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Delete(h.ctx, h.Name, metav1.DeleteOptions{})
+}
+
+func (h *HPA) Update() error {
+	if h.err != nil {
+		return h.err
+	}
+	_, err := h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Update(h.ctx, h.HorizontalPodAutoscaler, metav1.UpdateOptions{})
+	return err
+}
+
+func (h *HPA) Get() (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	return h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Get(h.ctx, h.Name, metav1.GetOptions{})
+}
+
+func (h *HPA) Empty() bool {
+	if h.err != nil {
+		return false
+	}
+	_, err := h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Get(h.ctx, h.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (h *HPA) CreateOrUpdate() error {
+	if h.err != nil {
+		return h.err
+	}
+	_, err := h.client.AutoscalingV2().HorizontalPodAutoscalers(h.Namespace).Get(h.ctx, h.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return h.Create()
+		}
+		return err
+	}
+	return h.Update()
+}
+
+func (h *HPA) Equal(keys []string) bool {
+	if h.err != nil {
+		return false
+	}
+	hpa, err := h.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(h.HorizontalPodAutoscaler, hpa, keys)
+}
+
+// Status returns the current and desired replicas, the current metrics and
+// the conditions of the autoscaler in the cluster.
+func (h *HPA) Status() (*autoscalingv2.HorizontalPodAutoscalerStatus, error) {
+	hpa, err := h.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &hpa.Status, nil
+}
+
+func scalingRules(stabilizationWindowSeconds int32, selectPolicy autoscalingv2.ScalingPolicySelect, policies []autoscalingv2.HPAScalingPolicy) *autoscalingv2.HPAScalingRules {
+	rules := &autoscalingv2.HPAScalingRules{
+		StabilizationWindowSeconds: &stabilizationWindowSeconds,
+		Policies:                   policies,
+	}
+	if selectPolicy != "" {
+		rules.SelectPolicy = &selectPolicy
+	}
+	return rules
+}
@@ -0,0 +1,115 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/24 16:13:58
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHPA(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).Metadata("inference", "default")
+	hpa := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).
+		ResourceUtilization(v1.ResourceCPU, 70).
+		ResourceAverageValue(v1.ResourceMemory, resource.MustParse("20Gi")).
+		PodsMetric("queue_length", resource.MustParse("10")).
+		ExternalMetric("pubsub_backlog", map[string]string{"topic": "requests"}, resource.MustParse("100")).
+		ScaleUp(0, autoscalingv2.MaxChangePolicySelect, []autoscalingv2.HPAScalingPolicy{
+			{Type: autoscalingv2.PodsScalingPolicy, Value: 4, PeriodSeconds: 60},
+		}).
+		ScaleDown(300, "", []autoscalingv2.HPAScalingPolicy{
+			{Type: autoscalingv2.PercentScalingPolicy, Value: 10, PeriodSeconds: 60},
+		})
+
+	ref := hpa.Spec.ScaleTargetRef
+	if ref.Kind != "Deployment" || ref.Name != "inference" || ref.APIVersion != "apps/v1" {
+		t.Errorf("unexpected target %v", ref)
+	}
+	if len(hpa.Spec.Metrics) != 4 {
+		t.Errorf("expect 4 metrics, got %d", len(hpa.Spec.Metrics))
+	}
+	if *hpa.Spec.Behavior.ScaleUp.SelectPolicy != autoscalingv2.MaxChangePolicySelect || hpa.Spec.Behavior.ScaleDown.SelectPolicy != nil {
+		t.Errorf("unexpected behavior %v", hpa.Spec.Behavior)
+	}
+	if err := hpa.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := hpa.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Status = autoscalingv2.HorizontalPodAutoscalerStatus{
+		CurrentReplicas: 3,
+		DesiredReplicas: 5,
+		Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+			{Type: autoscalingv2.ScalingActive, Status: v1.ConditionTrue},
+		},
+	}
+	if _, err := client.AutoscalingV2().HorizontalPodAutoscalers("default").UpdateStatus(hpa.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	status, err := hpa.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.CurrentReplicas != 3 || status.DesiredReplicas != 5 || len(status.Conditions) != 1 {
+		t.Errorf("unexpected status %v", status)
+	}
+}
+
+func TestHPAEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).Metadata("inference", "default")
+	hpa := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).ResourceUtilization(v1.ResourceCPU, 70)
+	if err := hpa.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !hpa.Equal(nil) {
+		t.Error("expect equal to the created autoscaler")
+	}
+	other := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 32).ResourceUtilization(v1.ResourceCPU, 70)
+	if other.Equal(nil) {
+		t.Error("expect not equal with other max replicas")
+	}
+	other = NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).ResourceUtilization(v1.ResourceCPU, 50)
+	if other.Equal(nil) {
+		t.Error("expect not equal with another utilization")
+	}
+}
+
+func TestHPAEqualAverageValue(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).Metadata("inference", "default")
+	hpa := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).ResourceAverageValue(v1.ResourceMemory, resource.MustParse("1Gi"))
+	if err := hpa.Create(); err != nil {
+		t.Fatal(err)
+	}
+	same := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).ResourceAverageValue(v1.ResourceMemory, resource.MustParse("1024Mi"))
+	if !same.Equal(nil) {
+		t.Error("expect equal with the same average value in other units")
+	}
+	other := NewHPA(nil).LinkClient(client).Metadata("inference", "default").
+		TargetDeployment(deployment).Replicas(2, 16).ResourceAverageValue(v1.ResourceMemory, resource.MustParse("8Gi"))
+	if other.Equal(nil) {
+		t.Error("expect not equal with another average value")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/19 10:12:37
+ Desc     : ingress
+*/
+
+package kube
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Ingress struct {
+	*LinkInfo
+	*networkingv1.Ingress
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewIngress(ctx context.Context) *Ingress {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &Ingress{
+		Ingress: &networkingv1.Ingress{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Ingress",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       networkingv1.IngressSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (i *Ingress) Link(region, config string, opts ...ClientOption) *Ingress {
+	i.Region = region
+	i.Config = config
+	i.client, i.err = NewKubeClient(region, config, opts...)
+	return i
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (i *Ingress) LinkClient(client kubernetes.Interface) *Ingress {
+	i.client, i.err = NewKubeClientFor(client), nil
+	return i
+}
+
+func (i *Ingress) Metadata(name, namespace string) *Ingress {
+	i.Name, i.Namespace = name, namespace
+	return i
+}
+
+func (i *Ingress) Labels(labels map[string]string) *Ingress {
+	if i.Ingress.Labels == nil {
+		i.Ingress.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		i.Ingress.Labels[k] = v
+	}
+	return i
+}
+
+func (i *Ingress) Annotations(annotations map[string]string) *Ingress {
+	if i.Ingress.Annotations == nil {
+		i.Ingress.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		i.Ingress.Annotations[k] = v
+	}
+	return i
+}
+
+func (i *Ingress) Class(className string) *Ingress {
+	i.Ingress.Spec.IngressClassName = &className
+	return i
+}
+
+// Rule routes the path of the host to the port of the service, the port is
+// either the name or the number of a service port. The paths of the same
+// host are merged into one rule.
+func (i *Ingress) Rule(host, path string, pathType networkingv1.PathType, service *Service, port intstr.IntOrString) *Ingress {
+	if service == nil {
+		return i
+	}
+	ingressPath := networkingv1.HTTPIngressPath{
+		Path:     path,
+		PathType: &pathType,
+		Backend:  ingressBackend(service, port),
+	}
+	for idx, rule := range i.Ingress.Spec.Rules {
+		if rule.Host == host && rule.HTTP != nil {
+			i.Ingress.Spec.Rules[idx].HTTP.Paths = append(rule.HTTP.Paths, ingressPath)
+			return i
+		}
+	}
+	i.Ingress.Spec.Rules = append(i.Ingress.Spec.Rules, networkingv1.IngressRule{
+		Host: host,
+		IngressRuleValue: networkingv1.IngressRuleValue{
+			HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{ingressPath},
+			},
+		},
+	})
+	return i
+}
+
+// TLS terminates tls of the hosts with the certificate of the secret, which
+// must live in the namespace of the ingress.
+func (i *Ingress) TLS(hosts []string, secret *Secret) *Ingress {
+	if secret == nil {
+		return i
+	}
+	i.Ingress.Spec.TLS = append(i.Ingress.Spec.TLS, networkingv1.IngressTLS{
+		Hosts:      hosts,
+		SecretName: secret.Name,
+	})
+	return i
+}
+
+// DefaultBackend handles the requests which match no rule.
+func (i *Ingress) DefaultBackend(service *Service, port intstr.IntOrString) *Ingress {
+	if service == nil {
+		return i
+	}
+	backend := ingressBackend(service, port)
+	i.Ingress.Spec.DefaultBackend = &backend
+	return i
+}
+
+func (i *Ingress) Create() error {
+	if i.err != nil {
+		return i.err
+	}
+	_, err := i.client.NetworkingV1().Ingresses(i.Namespace).Create(i.ctx, i.Ingress, metav1.CreateOptions{})
+	return err
+}
+
+func (i *Ingress) Delete() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.client.NetworkingV1().Ingresses(i.Namespace).Delete(i.ctx, i.Name, metav1.DeleteOptions{})
+}
+
+func (i *Ingress) Update() error {
+	if i.err != nil {
+		return i.err
+	}
+	_, err := i.client.NetworkingV1().Ingresses(i.Namespace).Update(i.ctx, i.Ingress, metav1.UpdateOptions{})
+	return err
+}
+
+func (i *Ingress) Get() (*networkingv1.Ingress, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	return i.client.NetworkingV1().Ingresses(i.Namespace).Get(i.ctx, i.Name, metav1.GetOptions{})
+}
+
+func (i *Ingress) Empty() bool {
+	if i.err != nil {
+		return false
+	}
+	_, err := i.client.NetworkingV1().Ingresses(i.Namespace).Get(i.ctx, i.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (i *Ingress) CreateOrUpdate() error {
+	if i.err != nil {
+		return i.err
+	}
+	_, err := i.client.NetworkingV1().Ingresses(i.Namespace).Get(i.ctx, i.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return i.Create()
+		}
+		return err
+	}
+	return i.Update()
+}
+
+func (i *Ingress) Equal(keys []string) bool {
+	if i.err != nil {
+		return false
+	}
+	ingress, err := i.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(i.Ingress, ingress, keys)
+}
+
+// LoadBalancerAddresses returns the ips or hostnames assigned to the ingress
+// by the controller, it is empty until the ingress is admitted.
+func (i *Ingress) LoadBalancerAddresses() ([]string, error) {
+	ingress, err := i.Get()
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(ingress.Status.LoadBalancer.Ingress))
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	return addresses, nil
+}
+
+func ingressBackend(service *Service, port intstr.IntOrString) networkingv1.IngressBackend {
+	backend := networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: service.Name,
+		},
+	}
+	if port.Type == intstr.String {
+		backend.Service.Port.Name = port.StrVal
+	} else {
+		backend.Service.Port.Number = port.IntVal
+	}
+	return backend
+}
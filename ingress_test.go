@@ -0,0 +1,87 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/19 15:08:12
+ Desc     :
+*/
+
+package kube
+
+import (
+	"reflect"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIngress(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	notebook := NewService(nil).Metadata("notebook", "default")
+	inference := NewService(nil).Metadata("inference", "default")
+	cert := NewSecret(nil).Metadata("example-tls", "default")
+	ingress := NewIngress(nil).LinkClient(client).Metadata("gateway", "default").Class("nginx").
+		Annotations(map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "0"}).
+		Rule("ai.example.com", "/notebook", networkingv1.PathTypePrefix, notebook, intstr.FromString("http")).
+		Rule("ai.example.com", "/v1/models", networkingv1.PathTypeExact, inference, intstr.FromInt(8080)).
+		TLS([]string{"ai.example.com"}, cert).
+		DefaultBackend(notebook, intstr.FromInt(80))
+
+	if len(ingress.Spec.Rules) != 1 || len(ingress.Spec.Rules[0].HTTP.Paths) != 2 {
+		t.Fatalf("expect the paths of one host merged, got %v", ingress.Spec.Rules)
+	}
+	backend := ingress.Spec.Rules[0].HTTP.Paths[1].Backend.Service
+	if backend.Name != "inference" || backend.Port.Number != 8080 || backend.Port.Name != "" {
+		t.Errorf("unexpected backend %v", backend)
+	}
+	if ingress.Spec.TLS[0].SecretName != "example-tls" {
+		t.Errorf("unexpected tls %v", ingress.Spec.TLS)
+	}
+	if err := ingress.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := ingress.LoadBalancerAddresses()
+	if err != nil || len(addresses) != 0 {
+		t.Fatalf("expect no address before admitted, got %v, %v", addresses, err)
+	}
+	got, err := ingress.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{
+		{IP: "10.0.0.10"},
+		{Hostname: "lb.example.com"},
+	}
+	if _, err := client.NetworkingV1().Ingresses("default").UpdateStatus(ingress.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	addresses, err = ingress.LoadBalancerAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(addresses, []string{"10.0.0.10", "lb.example.com"}) {
+		t.Errorf("unexpected addresses %v", addresses)
+	}
+}
+
+func TestIngressEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	notebook := NewService(nil).Metadata("notebook", "default")
+	ingress := NewIngress(nil).LinkClient(client).Metadata("gateway", "default").Class("nginx").
+		Rule("ai.example.com", "/notebook", networkingv1.PathTypePrefix, notebook, intstr.FromInt(80))
+	if err := ingress.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !ingress.Equal(nil) {
+		t.Error("expect equal to the created ingress")
+	}
+	other := NewIngress(nil).LinkClient(client).Metadata("gateway", "default").Class("nginx").
+		Rule("ai.example.com", "/lab", networkingv1.PathTypePrefix, notebook, intstr.FromInt(80))
+	if other.Equal(nil) {
+		t.Error("expect not equal with another path")
+	}
+}
@@ -0,0 +1,330 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/11 16:40:12
+ Desc     : batch job
+*/
+
+package kube
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/piaobeizu/kube/base"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Job struct {
+	*LinkInfo
+	*batchv1.Job
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewJob(ctx context.Context) *Job {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &Job{
+		Job: &batchv1.Job{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       batchv1.JobSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (j *Job) Link(region, config string, opts ...ClientOption) *Job {
+	j.Region = region
+	j.Config = config
+	j.client, j.err = NewKubeClient(region, config, opts...)
+	return j
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (j *Job) LinkClient(client kubernetes.Interface) *Job {
+	j.client, j.err = NewKubeClientFor(client), nil
+	return j
+}
+
+func (j *Job) Metadata(name, namespace string) *Job {
+	j.Name, j.Namespace = name, namespace
+	return j
+}
+
+func (j *Job) Labels(labels map[string]string) *Job {
+	if j.Job.Labels == nil {
+		j.Job.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		j.Job.Labels[k] = v
+	}
+	return j
+}
+
+func (j *Job) Annotations(annotations map[string]string) *Job {
+	if j.Job.Annotations == nil {
+		j.Job.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		j.Job.Annotations[k] = v
+	}
+	return j
+}
+
+// Template sets the pod template, the restart policy defaults to Never as
+// a job does not accept Always.
+func (j *Job) Template(pod *PodTemplate) *Job {
+	if pod == nil {
+		return j
+	}
+	j.Job.Spec.Template = pod.Template
+	if j.Job.Spec.Template.Spec.RestartPolicy == "" {
+		j.Job.Spec.Template.Spec.RestartPolicy = v1.RestartPolicyNever
+	}
+	return j
+}
+
+func (j *Job) Parallelism(parallelism int32) *Job {
+	j.Job.Spec.Parallelism = &parallelism
+	return j
+}
+
+func (j *Job) Completions(completions int32) *Job {
+	j.Job.Spec.Completions = &completions
+	return j
+}
+
+func (j *Job) BackoffLimit(limit int32) *Job {
+	j.Job.Spec.BackoffLimit = &limit
+	return j
+}
+
+func (j *Job) ActiveDeadlineSeconds(seconds int64) *Job {
+	j.Job.Spec.ActiveDeadlineSeconds = &seconds
+	return j
+}
+
+func (j *Job) TTLSecondsAfterFinished(seconds int32) *Job {
+	j.Job.Spec.TTLSecondsAfterFinished = &seconds
+	return j
+}
+
+// CompletionMode sets the completion mode, with batchv1.IndexedCompletion
+// every pod gets its index in the annotation batch.kubernetes.io/job-completion-index.
+func (j *Job) CompletionMode(mode batchv1.CompletionMode) *Job {
+	j.Job.Spec.CompletionMode = &mode
+	return j
+}
+
+// PodFailurePolicyRule appends a rule of the pod failure policy, the policy
+// requires the restart policy of the pod template to be Never.
+func (j *Job) PodFailurePolicyRule(rule batchv1.PodFailurePolicyRule) *Job {
+	if j.Job.Spec.PodFailurePolicy == nil {
+		j.Job.Spec.PodFailurePolicy = &batchv1.PodFailurePolicy{
+			Rules: make([]batchv1.PodFailurePolicyRule, 0),
+		}
+	}
+	j.Job.Spec.PodFailurePolicy.Rules = append(j.Job.Spec.PodFailurePolicy.Rules, rule)
+	return j
+}
+
+// ResourceStrategy fills the pod template from the strategy: requests and
+// limits of the containers which declare none, scheduler name, priority
+// class, labels, annotations, tolerations and node affinity. It must be
+// called after Template.
+func (j *Job) ResourceStrategy(strategy base.ResourceStrategy) *Job {
+	if strategy == nil {
+		return j
+	}
+	applyResourceStrategy(&j.Job.Spec.Template, strategy)
+	return j
+}
+
+func (j *Job) Create() error {
+	if j.err != nil {
+		return j.err
+	}
+	_, err := j.client.BatchV1().Jobs(j.Namespace).
+		Create(j.ctx, j.Job, metav1.CreateOptions{})
+	return err
+}
+
+// Delete removes the job together with its pods.
+func (j *Job) Delete() error {
+	if j.err != nil {
+		return j.err
+	}
+	propagation := metav1.DeletePropagationBackground
+	return j.client.BatchV1().Jobs(j.Namespace).
+		Delete(j.ctx, j.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
+// Update changes the mutable fields of the job in the cluster: labels,
+// annotations, parallelism, active deadline, ttl and suspend. The template
+// and the selector can not be changed once the job is created, recreate the
+// job to change them.
+func (j *Job) Update() error {
+	job, err := j.Get()
+	if err != nil {
+		return err
+	}
+	job.Labels = mergeStringMap(job.Labels, j.Job.Labels)
+	job.Annotations = mergeStringMap(job.Annotations, j.Job.Annotations)
+	if j.Job.Spec.Parallelism != nil {
+		job.Spec.Parallelism = j.Job.Spec.Parallelism
+	}
+	if j.Job.Spec.ActiveDeadlineSeconds != nil {
+		job.Spec.ActiveDeadlineSeconds = j.Job.Spec.ActiveDeadlineSeconds
+	}
+	if j.Job.Spec.TTLSecondsAfterFinished != nil {
+		job.Spec.TTLSecondsAfterFinished = j.Job.Spec.TTLSecondsAfterFinished
+	}
+	if j.Job.Spec.Suspend != nil {
+		job.Spec.Suspend = j.Job.Spec.Suspend
+	}
+	_, err = j.client.BatchV1().Jobs(j.Namespace).Update(j.ctx, job, metav1.UpdateOptions{})
+	return err
+}
+
+func (j *Job) Get() (*batchv1.Job, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	return j.client.BatchV1().Jobs(j.Namespace).Get(j.ctx, j.Name, metav1.GetOptions{})
+}
+
+func (j *Job) Empty() bool {
+	if j.err != nil {
+		return false
+	}
+	_, err := j.client.BatchV1().Jobs(j.Namespace).Get(j.ctx, j.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (j *Job) CreateOrUpdate() error {
+	if j.err != nil {
+		return j.err
+	}
+	_, err := j.client.BatchV1().Jobs(j.Namespace).Get(j.ctx, j.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return j.Create()
+		}
+		return err
+	}
+	return j.Update()
+}
+
+func (j *Job) Equal(keys []string) bool {
+	if j.err != nil {
+		return false
+	}
+	job, err := j.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(j.Job, job, keys)
+}
+
+func applyResourceStrategy(template *v1.PodTemplateSpec, strategy base.ResourceStrategy) {
+	region := strategy.GetRegion()
+	requests := strategy.Requests().ResourceList(region)
+	limits := strategy.Limits().ResourceList(region)
+	for i := range template.Spec.Containers {
+		resources := &template.Spec.Containers[i].Resources
+		if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+			resources.Requests = requests.DeepCopy()
+			resources.Limits = limits.DeepCopy()
+		}
+	}
+
+	scheduling := strategy.SchedulingStrategy()
+	if scheduling == nil {
+		return
+	}
+	if labels := scheduling.Labels(); len(labels) > 0 {
+		if template.Labels == nil {
+			template.Labels = make(map[string]string)
+		}
+		for k, v := range labels {
+			template.Labels[k] = v
+		}
+	}
+	if annotations := scheduling.Annotations(); len(annotations) > 0 {
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string)
+		}
+		for k, v := range annotations {
+			template.Annotations[k] = v
+		}
+	}
+	template.Spec.SchedulerName = scheduling.SchedulerName()
+	template.Spec.PriorityClassName = scheduling.PriorityClassName()
+	for _, toleration := range scheduling.Tolerations() {
+		if !hasToleration(template.Spec.Tolerations, toleration) {
+			template.Spec.Tolerations = append(template.Spec.Tolerations, toleration)
+		}
+	}
+
+	terms := scheduling.NodeSelectorTerms()
+	preferred := scheduling.PreferredSchedulingTerms()
+	if len(terms) == 0 && len(preferred) == 0 {
+		return
+	}
+	if template.Spec.Affinity == nil {
+		template.Spec.Affinity = &v1.Affinity{}
+	}
+	if template.Spec.Affinity.NodeAffinity == nil {
+		template.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	nodeAffinity := template.Spec.Affinity.NodeAffinity
+	if len(terms) > 0 {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{
+			NodeSelectorTerms: terms,
+		}
+	}
+	for _, term := range preferred {
+		if !hasPreferredTerm(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term) {
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term)
+		}
+	}
+}
+
+// hasToleration keeps applyResourceStrategy from adding a toleration twice
+// when the strategy is applied again.
+func hasToleration(tolerations []v1.Toleration, toleration v1.Toleration) bool {
+	for _, t := range tolerations {
+		if reflect.DeepEqual(t, toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPreferredTerm(terms []v1.PreferredSchedulingTerm, term v1.PreferredSchedulingTerm) bool {
+	for _, t := range terms {
+		if reflect.DeepEqual(t, term) {
+			return true
+		}
+	}
+	return false
+}
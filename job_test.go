@@ -0,0 +1,129 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/11 18:22:37
+ Desc     :
+*/
+
+package kube
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/piaobeizu/kube/base"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestJobResourceStrategy(t *testing.T) {
+	sidecar := NewContainer(nil).Metadata("sidecar").Image("busybox").Requests(100, 128, 0, 0, "")
+	trainer := NewContainer(nil).Metadata("trainer").Image("pytorch")
+	template := NewPodTemplate(nil).Container(*trainer).Container(*sidecar)
+	sidecarResources := *sidecar.Resources.DeepCopy()
+	strategy := base.HETStrategy{
+		Raw: base.Resource{
+			CPUNum:           8,
+			GPUNum:           2,
+			MemorySize:       32,
+			EphemeralStorage: 100,
+			GPUSeries:        base.GPUSeriesA800,
+		},
+		Region: "dev",
+	}
+	job := NewJob(nil).Metadata("train", "default").Template(template).
+		CompletionMode(batchv1.IndexedCompletion).ResourceStrategy(strategy)
+
+	spec := job.Job.Spec.Template.Spec
+	if spec.RestartPolicy != v1.RestartPolicyNever {
+		t.Errorf("unexpected restart policy %s", spec.RestartPolicy)
+	}
+	if spec.SchedulerName != base.GPUSchedulerName || spec.PriorityClassName != base.A800PriorityClass {
+		t.Errorf("unexpected scheduler %s or priority class %s", spec.SchedulerName, spec.PriorityClassName)
+	}
+	requests := spec.Containers[0].Resources.Requests
+	if cpu := requests[v1.ResourceCPU]; cpu.Value() != 4 {
+		t.Errorf("expect 4 cpu requested, got %s", cpu.String())
+	}
+	if gpu := spec.Containers[0].Resources.Limits[base.ResourceNvidiaGPU]; gpu.Value() != 2 {
+		t.Errorf("expect 2 gpu limited, got %s", gpu.String())
+	}
+	if !reflect.DeepEqual(spec.Containers[1].Resources, sidecarResources) {
+		t.Errorf("expect the resources of the sidecar untouched, got %v", spec.Containers[1].Resources)
+	}
+	if memory := spec.Containers[1].Resources.Requests[v1.ResourceMemory]; memory.String() != "128Mi" {
+		t.Errorf("expect 128Mi memory requested by the sidecar, got %s", memory.String())
+	}
+	if len(spec.Tolerations) == 0 {
+		t.Error("expect tolerations from the strategy")
+	}
+	// applying the strategy again adds nothing
+	tolerations := len(spec.Tolerations)
+	job.ResourceStrategy(strategy)
+	if got := len(job.Job.Spec.Template.Spec.Tolerations); got != tolerations {
+		t.Errorf("expect %d tolerations after applying twice, got %d", tolerations, got)
+	}
+	affinity := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if affinity == nil || len(affinity.NodeSelectorTerms) != 1 {
+		t.Errorf("unexpected node affinity %v", affinity)
+	}
+}
+
+func TestJobEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	job := NewJob(nil).LinkClient(client).Metadata("train", "default").Parallelism(1)
+	if err := job.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !job.Equal(nil) {
+		t.Error("expect equal to the created job")
+	}
+	other := NewJob(nil).LinkClient(client).Metadata("train", "default").Parallelism(9)
+	if other.Equal(nil) {
+		t.Error("expect not equal with other parallelism")
+	}
+}
+
+func TestJobUpdate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	container := NewContainer(nil).Metadata("trainer").Image("pytorch")
+	job := NewJob(nil).LinkClient(client).Metadata("train", "default").
+		Template(NewPodTemplate(nil).Container(*container)).Parallelism(1)
+	if err := job.Create(); err != nil {
+		t.Fatal(err)
+	}
+	// the server sets the selector and the labels of the template
+	got, err := job.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "uid-train"}}
+	got.Spec.Template.Labels = map[string]string{"controller-uid": "uid-train"}
+	if _, err := client.BatchV1().Jobs("default").Update(job.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	other := NewContainer(nil).Metadata("trainer").Image("tensorflow")
+	scaled := NewJob(nil).LinkClient(client).Metadata("train", "default").
+		Labels(map[string]string{"app": "train"}).
+		Template(NewPodTemplate(nil).Container(*other)).Parallelism(4)
+	if err := scaled.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = job.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.Spec.Parallelism != 4 || got.Labels["app"] != "train" {
+		t.Errorf("expect parallelism and labels updated, got %d and %v", *got.Spec.Parallelism, got.Labels)
+	}
+	if got.Spec.Selector == nil || got.Spec.Template.Labels["controller-uid"] != "uid-train" {
+		t.Errorf("expect the selector and template labels kept, got %v", got.Spec.Selector)
+	}
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "pytorch" {
+		t.Errorf("expect the template untouched, got image %s", image)
+	}
+}
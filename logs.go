@@ -0,0 +1,188 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/04 10:33:51
+ Desc     : container logs of pods
+*/
+
+package kube
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogOptions selects the logs of a container, the zero value returns all the
+// logs of the running container.
+type LogOptions struct {
+	// Follow streams the new logs until the container stops or the reader is
+	// closed
+	Follow bool
+	// TailLines returns the last lines only, 0 returns all the lines
+	TailLines int64
+	// SinceSeconds and SinceTime return the logs newer than them, only one of
+	// them can be set
+	SinceSeconds int64
+	SinceTime    time.Time
+	Timestamps   bool
+	// Previous returns the logs of the last terminated container, e.g. the
+	// one which crashed before a restart
+	Previous bool
+}
+
+func (o LogOptions) podLogOptions(container string) *v1.PodLogOptions {
+	opts := &v1.PodLogOptions{
+		Container:  container,
+		Follow:     o.Follow,
+		Timestamps: o.Timestamps,
+		Previous:   o.Previous,
+	}
+	if o.TailLines > 0 {
+		opts.TailLines = &o.TailLines
+	}
+	if o.SinceSeconds > 0 {
+		opts.SinceSeconds = &o.SinceSeconds
+	}
+	if !o.SinceTime.IsZero() {
+		opts.SinceTime = &metav1.Time{Time: o.SinceTime}
+	}
+	return opts
+}
+
+// Logs streams the logs of the container, the container can be empty for a
+// pod with a single container. The caller must close the reader.
+func (p *Pod) Logs(container string, opts LogOptions) (io.ReadCloser, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if opts.SinceSeconds > 0 && !opts.SinceTime.IsZero() {
+		return nil, fmt.Errorf("only one of SinceSeconds and SinceTime can be set")
+	}
+	return p.client.CoreV1().Pods(p.Namespace).GetLogs(p.Name, opts.podLogOptions(container)).Stream(p.ctx)
+}
+
+// LogsBySelector fans in the logs of the pods matching the selector in the
+// namespace of the pod. Every line is prefixed with [pod/<pod>/<container>],
+// the logs of all the containers are returned when the container is empty.
+// Pending pods are skipped, a container whose logs can not be read reports
+// the error as its only line. The caller must close the reader, which stops
+// the streams.
+func (p *Pod) LogsBySelector(selector map[string]string, container string, opts LogOptions) (io.ReadCloser, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("selector of the logs is empty")
+	}
+	if opts.SinceSeconds > 0 && !opts.SinceTime.IsZero() {
+		return nil, fmt.Errorf("only one of SinceSeconds and SinceTime can be set")
+	}
+	ctx, cancel := context.WithCancel(p.ctx)
+	pods, err := selectPods(ctx, p.client, p.Namespace, &metav1.LabelSelector{MatchLabels: selector})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	var streams []*prefixedStream
+	closeAll := func() {
+		cancel()
+		for _, s := range streams {
+			s.Close()
+		}
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodPending {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			if container != "" && c.Name != container {
+				continue
+			}
+			stream, err := p.client.CoreV1().Pods(p.Namespace).GetLogs(pod.Name, opts.podLogOptions(c.Name)).Stream(ctx)
+			if err != nil {
+				stream = io.NopCloser(strings.NewReader(fmt.Sprintf("error: %v\n", err)))
+			}
+			streams = append(streams, &prefixedStream{
+				ReadCloser: stream,
+				prefix:     fmt.Sprintf("[pod/%s/%s] ", pod.Name, c.Name),
+			})
+		}
+	}
+	reader, writer := io.Pipe()
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s *prefixedStream) {
+			defer wg.Done()
+			err := s.copyLines(writer, &mu)
+			if err != nil && ctx.Err() == nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		writer.CloseWithError(firstErr)
+	}()
+	return &fanInReader{PipeReader: reader, close: closeAll}, nil
+}
+
+// prefixedStream is the log stream of a container.
+type prefixedStream struct {
+	io.ReadCloser
+	prefix string
+}
+
+// copyLines writes whole lines so that the lines of the streams are not
+// interleaved, mu guards the writer.
+func (s *prefixedStream) copyLines(w io.Writer, mu *sync.Mutex) error {
+	reader := bufio.NewReader(s)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if line[len(line)-1] != '\n' {
+				line += "\n"
+			}
+			mu.Lock()
+			_, werr := io.WriteString(w, s.prefix+line)
+			mu.Unlock()
+			if werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type fanInReader struct {
+	*io.PipeReader
+	close func()
+}
+
+func (r *fanInReader) Close() error {
+	r.close()
+	return r.PipeReader.Close()
+}
@@ -0,0 +1,120 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/04 15:06:29
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPodLogs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pod := NewPod(nil).LinkClient(client).Metadata("trainer-0", "default")
+	reader, err := pod.Logs("trainer", LogOptions{Follow: true, TailLines: 10, Previous: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "fake logs" {
+		t.Fatalf("unexpected logs %q", data)
+	}
+	var opts *v1.PodLogOptions
+	for _, action := range client.Actions() {
+		if action.GetSubresource() == "log" {
+			opts = action.(k8stesting.GenericAction).GetValue().(*v1.PodLogOptions)
+		}
+	}
+	if opts == nil || opts.Container != "trainer" || !opts.Follow || !opts.Previous ||
+		opts.TailLines == nil || *opts.TailLines != 10 || opts.SinceSeconds != nil {
+		t.Fatalf("unexpected log options %+v", opts)
+	}
+
+	if _, err := pod.Logs("", LogOptions{SinceSeconds: 60, SinceTime: metav1.Now().Time}); err == nil {
+		t.Fatal("expected error for both SinceSeconds and SinceTime")
+	}
+}
+
+func TestPodLogsBySelector(t *testing.T) {
+	ctx := context.TODO()
+	client := fake.NewSimpleClientset()
+	for _, name := range []string{"trainer-0", "trainer-1"} {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"job": "trainer"}},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "trainer"},
+				{Name: "sidecar"},
+			}},
+		}
+		if _, err := client.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a pending pod has no logs yet
+	pending := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "trainer-2", Namespace: "default", Labels: map[string]string{"job": "trainer"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "trainer"}}},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+	if _, err := client.CoreV1().Pods("default").Create(ctx, pending, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "trainer"}}}}
+	if _, err := client.CoreV1().Pods("default").Create(ctx, other, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	pod := NewPod(nil).LinkClient(client).Metadata("", "default")
+	if _, err := pod.LogsBySelector(nil, "", LogOptions{}); err == nil {
+		t.Fatal("expect error of an empty selector")
+	}
+	readLines := func(container string) []string {
+		reader, err := pod.LogsBySelector(map[string]string{"job": "trainer"}, container, LogOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		sort.Strings(lines)
+		return lines
+	}
+
+	expected := []string{
+		"[pod/trainer-0/sidecar] fake logs",
+		"[pod/trainer-0/trainer] fake logs",
+		"[pod/trainer-1/sidecar] fake logs",
+		"[pod/trainer-1/trainer] fake logs",
+	}
+	if lines := readLines(""); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	expected = []string{
+		"[pod/trainer-0/trainer] fake logs",
+		"[pod/trainer-1/trainer] fake logs",
+	}
+	if lines := readLines("trainer"); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+}
@@ -0,0 +1,357 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/13 10:18:26
+ Desc     : namespace and the bootstrap of a tenant
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	DefaultQuotaName      = "default-quota"
+	DefaultLimitRangeName = "default-limits"
+)
+
+type Namespace struct {
+	*LinkInfo
+	*v1.Namespace
+	ctx    context.Context
+	client *KubeClient
+	// objects bootstrapped together with the namespace
+	quota          *v1.ResourceQuota
+	limitRange     *v1.LimitRange
+	pullSecrets    []*Secret
+	serviceAccount *ServiceAccount
+	policies       []*NetworkPolicy
+	// terminationTimeout is how long Delete waits for the namespace to be
+	// terminated
+	terminationTimeout time.Duration
+}
+
+func NewNamespace(ctx context.Context) *Namespace {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &Namespace{
+		Namespace: &v1.Namespace{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Namespace",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+		},
+		LinkInfo:           &LinkInfo{},
+		client:             nil,
+		ctx:                ctx,
+		terminationTimeout: 5 * time.Minute,
+	}
+}
+
+func (n *Namespace) Link(region, config string, opts ...ClientOption) *Namespace {
+	n.Region = region
+	n.Config = config
+	n.client, n.err = NewKubeClient(region, config, opts...)
+	return n
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (n *Namespace) LinkClient(client kubernetes.Interface) *Namespace {
+	n.client, n.err = NewKubeClientFor(client), nil
+	return n
+}
+
+func (n *Namespace) Metadata(name string) *Namespace {
+	n.Name = name
+	return n
+}
+
+func (n *Namespace) Labels(labels map[string]string) *Namespace {
+	if n.Namespace.Labels == nil {
+		n.Namespace.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		n.Namespace.Labels[k] = v
+	}
+	return n
+}
+
+func (n *Namespace) Annotations(annotations map[string]string) *Namespace {
+	if n.Namespace.Annotations == nil {
+		n.Namespace.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		n.Namespace.Annotations[k] = v
+	}
+	return n
+}
+
+// Quota bootstraps the resource quota of the namespace, extended resources
+// like nvidia.com/gpu or baidu.com/cgpu are quoted as requests.
+func (n *Namespace) Quota(hard v1.ResourceList) *Namespace {
+	quota := make(v1.ResourceList, len(hard))
+	for name, quantity := range hard {
+		quota[quotaResourceName(name)] = quantity
+	}
+	n.quota = &v1.ResourceQuota{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ResourceQuota",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultQuotaName,
+		},
+		Spec: v1.ResourceQuotaSpec{
+			Hard: quota,
+		},
+	}
+	return n
+}
+
+// LimitRange bootstraps the default limits and requests of the containers
+// which declare none.
+func (n *Namespace) LimitRange(defaultLimits, defaultRequests v1.ResourceList) *Namespace {
+	n.limitRange = &v1.LimitRange{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "LimitRange",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultLimitRangeName,
+		},
+		Spec: v1.LimitRangeSpec{
+			Limits: []v1.LimitRangeItem{
+				{
+					Type:           v1.LimitTypeContainer,
+					Default:        defaultLimits,
+					DefaultRequest: defaultRequests,
+				},
+			},
+		},
+	}
+	return n
+}
+
+// ImagePullSecret bootstraps a docker config secret, it is also referenced
+// by the service account of ServiceAccount.
+func (n *Namespace) ImagePullSecret(name string, dockerConfigJSON []byte) *Namespace {
+	secret := NewSecret(n.ctx).Metadata(name, "").
+		Type(v1.SecretTypeDockerConfigJson).
+		Data(map[string][]byte{v1.DockerConfigJsonKey: dockerConfigJSON})
+	n.pullSecrets = append(n.pullSecrets, secret)
+	return n
+}
+
+// ServiceAccount bootstraps the default service account of the tenant.
+func (n *Namespace) ServiceAccount(name string) *Namespace {
+	n.serviceAccount = NewServiceAccount(n.ctx).Metadata(name, "")
+	return n
+}
+
+// NetworkPolicy bootstraps the network policy to isolate the tenant.
+func (n *Namespace) NetworkPolicy(policy *NetworkPolicy) *Namespace {
+	if policy != nil {
+		n.policies = append(n.policies, policy)
+	}
+	return n
+}
+
+func (n *Namespace) TerminationTimeout(timeout time.Duration) *Namespace {
+	n.terminationTimeout = timeout
+	return n
+}
+
+// Create creates the namespace and bootstraps the quota, limit range, image
+// pull secrets, service account and network policies, the namespace is removed again when
+// the bootstrap fails.
+func (n *Namespace) Create() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.CoreV1().Namespaces().Create(n.ctx, n.Namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	if err := n.bootstrap(); err != nil {
+		if derr := n.client.CoreV1().Namespaces().Delete(n.ctx, n.Name, metav1.DeleteOptions{}); derr != nil {
+			return fmt.Errorf("bootstrap namespace %s error: %w, rollback error: %s", n.Name, err, derr.Error())
+		}
+		return fmt.Errorf("bootstrap namespace %s error: %w", n.Name, err)
+	}
+	return nil
+}
+
+// Delete deletes the namespace and waits until it is terminated.
+func (n *Namespace) Delete() error {
+	if n.err != nil {
+		return n.err
+	}
+	err := n.client.CoreV1().Namespaces().Delete(n.ctx, n.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+	return wait.PollUntilContextTimeout(n.ctx, time.Second, n.terminationTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			_, err := n.client.CoreV1().Namespaces().Get(ctx, n.Name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		})
+}
+
+// Update updates the namespace and the bootstrapped objects.
+func (n *Namespace) Update() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.CoreV1().Namespaces().Update(n.ctx, n.Namespace, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	return n.bootstrap()
+}
+
+func (n *Namespace) Get() (*v1.Namespace, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	return n.client.CoreV1().Namespaces().Get(n.ctx, n.Name, metav1.GetOptions{})
+}
+
+func (n *Namespace) List() (*v1.NamespaceList, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	return n.client.CoreV1().Namespaces().List(n.ctx, metav1.ListOptions{})
+}
+
+func (n *Namespace) Empty() bool {
+	if n.err != nil {
+		return false
+	}
+	_, err := n.client.CoreV1().Namespaces().Get(n.ctx, n.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (n *Namespace) CreateOrUpdate() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.CoreV1().Namespaces().Get(n.ctx, n.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return n.Create()
+		}
+		return err
+	}
+	return n.Update()
+}
+
+func (n *Namespace) Equal(keys []string) bool {
+	if n.err != nil {
+		return false
+	}
+	namespace, err := n.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{
+			"^Metadata.Labels.*$",
+			"^Metadata.Annotations.*$",
+		}
+	}
+	return ResourceEqual(n.Namespace, namespace, keys)
+}
+
+func (n *Namespace) bootstrap() error {
+	pullSecrets := make([]string, 0, len(n.pullSecrets))
+	for _, secret := range n.pullSecrets {
+		secret.Namespace = n.Name
+		if err := secret.LinkClient(n.client.Interface).CreateOrUpdate(); err != nil {
+			return fmt.Errorf("image pull secret %s: %w", secret.Name, err)
+		}
+		pullSecrets = append(pullSecrets, secret.Name)
+	}
+	if n.quota != nil {
+		n.quota.Namespace = n.Name
+		if err := n.upsertQuota(); err != nil {
+			return fmt.Errorf("resource quota %s: %w", n.quota.Name, err)
+		}
+	}
+	if n.limitRange != nil {
+		n.limitRange.Namespace = n.Name
+		if err := n.upsertLimitRange(); err != nil {
+			return fmt.Errorf("limit range %s: %w", n.limitRange.Name, err)
+		}
+	}
+	if n.serviceAccount != nil {
+		sa := n.serviceAccount
+		sa.Namespace = n.Name
+		sa.ServiceAccount.ImagePullSecrets = nil
+		sa.ImagePullSecrets(pullSecrets)
+		if err := sa.LinkClient(n.client.Interface).CreateOrUpdate(); err != nil {
+			return fmt.Errorf("service account %s: %w", sa.Name, err)
+		}
+	}
+	for _, policy := range n.policies {
+		policy.Namespace = n.Name
+		if err := policy.LinkClient(n.client.Interface).CreateOrUpdate(); err != nil {
+			return fmt.Errorf("network policy %s: %w", policy.Name, err)
+		}
+	}
+	return nil
+}
+
+func (n *Namespace) upsertQuota() error {
+	quotas := n.client.CoreV1().ResourceQuotas(n.Name)
+	_, err := quotas.Get(n.ctx, n.quota.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err = quotas.Create(n.ctx, n.quota, metav1.CreateOptions{})
+		}
+		return err
+	}
+	_, err = quotas.Update(n.ctx, n.quota, metav1.UpdateOptions{})
+	return err
+}
+
+func (n *Namespace) upsertLimitRange() error {
+	limitRanges := n.client.CoreV1().LimitRanges(n.Name)
+	_, err := limitRanges.Get(n.ctx, n.limitRange.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err = limitRanges.Create(n.ctx, n.limitRange, metav1.CreateOptions{})
+		}
+		return err
+	}
+	_, err = limitRanges.Update(n.ctx, n.limitRange, metav1.UpdateOptions{})
+	return err
+}
+
+// quotaResourceName prefixes the extended resources with "requests." as a
+// quota only accepts them in that form.
+func quotaResourceName(name v1.ResourceName) v1.ResourceName {
+	s := string(name)
+	if !strings.Contains(s, "/") || strings.HasPrefix(s, "requests.") || strings.HasPrefix(s, "limits.") {
+		return name
+	}
+	return v1.ResourceName("requests." + s)
+}
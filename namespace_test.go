@@ -0,0 +1,92 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/13 15:51:02
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNamespaceBootstrap(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ns := NewNamespace(nil).LinkClient(client).Metadata("tenant-a").
+		Labels(map[string]string{"tenant": "a"}).
+		Quota(v1.ResourceList{
+			v1.ResourceRequestsCPU: resource.MustParse("64"),
+			"nvidia.com/gpu":       resource.MustParse("8"),
+			"baidu.com/cgpu":       resource.MustParse("4"),
+		}).
+		LimitRange(v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}, v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}).
+		ImagePullSecret("registry", []byte(`{"auths":{}}`)).
+		ServiceAccount("tenant")
+	if err := ns.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.TODO()
+	quota, err := client.CoreV1().ResourceQuotas("tenant-a").Get(ctx, DefaultQuotaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []v1.ResourceName{v1.ResourceRequestsCPU, "requests.nvidia.com/gpu", "requests.baidu.com/cgpu"} {
+		if _, ok := quota.Spec.Hard[name]; !ok {
+			t.Errorf("expect %s in quota %v", name, quota.Spec.Hard)
+		}
+	}
+	if _, err := client.CoreV1().LimitRanges("tenant-a").Get(ctx, DefaultLimitRangeName, metav1.GetOptions{}); err != nil {
+		t.Error(err)
+	}
+	secret, err := client.CoreV1().Secrets("tenant-a").Get(ctx, "registry", metav1.GetOptions{})
+	if err != nil || secret.Type != v1.SecretTypeDockerConfigJson {
+		t.Errorf("unexpected pull secret %v, err: %v", secret, err)
+	}
+	sa, err := client.CoreV1().ServiceAccounts("tenant-a").Get(ctx, "tenant", metav1.GetOptions{})
+	if err != nil || len(sa.ImagePullSecrets) != 1 || sa.ImagePullSecrets[0].Name != "registry" {
+		t.Errorf("unexpected service account %v, err: %v", sa, err)
+	}
+
+	// update again keeps a single pull secret reference
+	if err := ns.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	if sa, _ := client.CoreV1().ServiceAccounts("tenant-a").Get(ctx, "tenant", metav1.GetOptions{}); len(sa.ImagePullSecrets) != 1 {
+		t.Errorf("unexpected pull secrets %v", sa.ImagePullSecrets)
+	}
+
+	if err := ns.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if !ns.Empty() {
+		t.Error("expect namespace deleted")
+	}
+}
+
+func TestNamespaceBootstrapRollback(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "resourcequotas", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("quota rejected")
+	})
+	ns := NewNamespace(nil).LinkClient(client).Metadata("tenant-b").
+		Quota(v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("8")})
+	if err := ns.Create(); err == nil {
+		t.Fatal("expect bootstrap error")
+	}
+	if _, err := ns.Get(); !errors.IsNotFound(err) {
+		t.Errorf("expect namespace rolled back, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/20 09:41:05
+ Desc     : network policy
+*/
+
+package kube
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+type NetworkPolicy struct {
+	*LinkInfo
+	*networkingv1.NetworkPolicy
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewNetworkPolicy(ctx context.Context) *NetworkPolicy {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &NetworkPolicy{
+		NetworkPolicy: &networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NetworkPolicy",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       networkingv1.NetworkPolicySpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (n *NetworkPolicy) Link(region, config string, opts ...ClientOption) *NetworkPolicy {
+	n.Region = region
+	n.Config = config
+	n.client, n.err = NewKubeClient(region, config, opts...)
+	return n
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (n *NetworkPolicy) LinkClient(client kubernetes.Interface) *NetworkPolicy {
+	n.client, n.err = NewKubeClientFor(client), nil
+	return n
+}
+
+func (n *NetworkPolicy) Metadata(name, namespace string) *NetworkPolicy {
+	n.Name, n.Namespace = name, namespace
+	return n
+}
+
+func (n *NetworkPolicy) Labels(labels map[string]string) *NetworkPolicy {
+	if n.NetworkPolicy.Labels == nil {
+		n.NetworkPolicy.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		n.NetworkPolicy.Labels[k] = v
+	}
+	return n
+}
+
+func (n *NetworkPolicy) Annotations(annotations map[string]string) *NetworkPolicy {
+	if n.NetworkPolicy.Annotations == nil {
+		n.NetworkPolicy.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		n.NetworkPolicy.Annotations[k] = v
+	}
+	return n
+}
+
+// PodSelector selects the pods the policy applies to, all pods of the
+// namespace are selected by default.
+func (n *NetworkPolicy) PodSelector(labels map[string]string) *NetworkPolicy {
+	n.NetworkPolicy.Spec.PodSelector = metav1.LabelSelector{
+		MatchLabels: labels,
+	}
+	return n
+}
+
+// Ingress allows the traffic of the rule to the selected pods.
+func (n *NetworkPolicy) Ingress(rule *NetworkPolicyRule) *NetworkPolicy {
+	n.policyType(networkingv1.PolicyTypeIngress)
+	if rule == nil {
+		return n
+	}
+	n.NetworkPolicy.Spec.Ingress = append(n.NetworkPolicy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+		Ports: rule.ports,
+		From:  rule.peers,
+	})
+	return n
+}
+
+// Egress allows the traffic of the rule from the selected pods.
+func (n *NetworkPolicy) Egress(rule *NetworkPolicyRule) *NetworkPolicy {
+	n.policyType(networkingv1.PolicyTypeEgress)
+	if rule == nil {
+		return n
+	}
+	n.NetworkPolicy.Spec.Egress = append(n.NetworkPolicy.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+		Ports: rule.ports,
+		To:    rule.peers,
+	})
+	return n
+}
+
+// DenyAllIngress isolates the selected pods from all incoming traffic unless
+// another ingress rule allows it.
+func (n *NetworkPolicy) DenyAllIngress() *NetworkPolicy {
+	return n.Ingress(nil)
+}
+
+// AllowSameNamespaceOnly isolates the selected pods from the traffic of other
+// namespaces.
+func (n *NetworkPolicy) AllowSameNamespaceOnly() *NetworkPolicy {
+	return n.Ingress(NewNetworkPolicyRule().Peer(map[string]string{}, nil))
+}
+
+// AllowDNSEgress allows the selected pods to resolve names through the
+// cluster dns, it is required together with any other egress restriction.
+func (n *NetworkPolicy) AllowDNSEgress() *NetworkPolicy {
+	return n.Egress(NewNetworkPolicyRule().
+		Peer(map[string]string{"k8s-app": "kube-dns"}, map[string]string{"kubernetes.io/metadata.name": "kube-system"}).
+		Port(v1.ProtocolUDP, intstr.FromInt(53)).
+		Port(v1.ProtocolTCP, intstr.FromInt(53)))
+}
+
+func (n *NetworkPolicy) policyType(policyType networkingv1.PolicyType) {
+	for _, t := range n.NetworkPolicy.Spec.PolicyTypes {
+		if t == policyType {
+			return
+		}
+	}
+	n.NetworkPolicy.Spec.PolicyTypes = append(n.NetworkPolicy.Spec.PolicyTypes, policyType)
+}
+
+func (n *NetworkPolicy) Create() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.NetworkingV1().NetworkPolicies(n.Namespace).Create(n.ctx, n.NetworkPolicy, metav1.CreateOptions{})
+	return err
+}
+
+func (n *NetworkPolicy) Delete() error {
+	if n.err != nil {
+		return n.err
+	}
+	return n.client.NetworkingV1().NetworkPolicies(n.Namespace).Delete(n.ctx, n.Name, metav1.DeleteOptions{})
+}
+
+func (n *NetworkPolicy) Update() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.NetworkingV1().NetworkPolicies(n.Namespace).Update(n.ctx, n.NetworkPolicy, metav1.UpdateOptions{})
+	return err
+}
+
+func (n *NetworkPolicy) Get() (*networkingv1.NetworkPolicy, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	return n.client.NetworkingV1().NetworkPolicies(n.Namespace).Get(n.ctx, n.Name, metav1.GetOptions{})
+}
+
+func (n *NetworkPolicy) Empty() bool {
+	if n.err != nil {
+		return false
+	}
+	_, err := n.client.NetworkingV1().NetworkPolicies(n.Namespace).Get(n.ctx, n.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (n *NetworkPolicy) CreateOrUpdate() error {
+	if n.err != nil {
+		return n.err
+	}
+	_, err := n.client.NetworkingV1().NetworkPolicies(n.Namespace).Get(n.ctx, n.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return n.Create()
+		}
+		return err
+	}
+	return n.Update()
+}
+
+func (n *NetworkPolicy) Equal(keys []string) bool {
+	if n.err != nil {
+		return false
+	}
+	policy, err := n.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(n.NetworkPolicy, policy, keys)
+}
+
+// NetworkPolicyRule is an ingress or egress rule, the traffic is allowed when
+// it matches any peer and any port, a rule without peers or ports matches
+// all of them.
+type NetworkPolicyRule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+func NewNetworkPolicyRule() *NetworkPolicyRule {
+	return &NetworkPolicyRule{}
+}
+
+// Peer matches the pods selected by both selectors, a nil podSelector
+// selects all pods of the namespaces and a nil namespaceSelector keeps the
+// namespace of the policy.
+func (r *NetworkPolicyRule) Peer(podSelector, namespaceSelector map[string]string) *NetworkPolicyRule {
+	peer := networkingv1.NetworkPolicyPeer{}
+	if podSelector != nil || namespaceSelector == nil {
+		peer.PodSelector = &metav1.LabelSelector{MatchLabels: podSelector}
+	}
+	if namespaceSelector != nil {
+		peer.NamespaceSelector = &metav1.LabelSelector{MatchLabels: namespaceSelector}
+	}
+	r.peers = append(r.peers, peer)
+	return r
+}
+
+func (r *NetworkPolicyRule) IPBlock(cidr string, except []string) *NetworkPolicyRule {
+	r.peers = append(r.peers, networkingv1.NetworkPolicyPeer{
+		IPBlock: &networkingv1.IPBlock{
+			CIDR:   cidr,
+			Except: except,
+		},
+	})
+	return r
+}
+
+// Port matches the port by number or by the name of a container port.
+func (r *NetworkPolicyRule) Port(protocol v1.Protocol, port intstr.IntOrString) *NetworkPolicyRule {
+	r.ports = append(r.ports, networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &port,
+	})
+	return r
+}
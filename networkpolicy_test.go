@@ -0,0 +1,81 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/20 14:22:47
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNetworkPolicyPresets(t *testing.T) {
+	policy := NewNetworkPolicy(nil).Metadata("isolation", "tenant-a").
+		DenyAllIngress().AllowSameNamespaceOnly().AllowDNSEgress()
+	if len(policy.Spec.PolicyTypes) != 2 {
+		t.Errorf("expect ingress and egress policy types, got %v", policy.Spec.PolicyTypes)
+	}
+	if len(policy.Spec.Ingress) != 1 {
+		t.Fatalf("expect one ingress rule, got %v", policy.Spec.Ingress)
+	}
+	peer := policy.Spec.Ingress[0].From[0]
+	if peer.PodSelector == nil || len(peer.PodSelector.MatchLabels) != 0 || peer.NamespaceSelector != nil {
+		t.Errorf("expect all pods of the same namespace, got %v", peer)
+	}
+	egress := policy.Spec.Egress[0]
+	if len(egress.Ports) != 2 || egress.Ports[0].Port.IntValue() != 53 || egress.To[0].NamespaceSelector == nil {
+		t.Errorf("unexpected dns egress %v", egress)
+	}
+
+	// a peer without selectors keeps the namespace of the policy
+	rule := NewNetworkPolicyRule().Peer(nil, nil).IPBlock("10.0.0.0/8", []string{"10.1.0.0/16"})
+	if rule.peers[0].PodSelector == nil || rule.peers[1].IPBlock.CIDR != "10.0.0.0/8" {
+		t.Errorf("unexpected peers %v", rule.peers)
+	}
+}
+
+func TestNamespaceNetworkPolicy(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ns := NewNamespace(nil).LinkClient(client).Metadata("tenant-a").
+		NetworkPolicy(NewNetworkPolicy(nil).Metadata("default-deny", "").DenyAllIngress()).
+		NetworkPolicy(NewNetworkPolicy(nil).Metadata("allow-dns", "").AllowDNSEgress())
+	if err := ns.Create(); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := client.NetworkingV1().NetworkPolicies("tenant-a").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 2 {
+		t.Fatalf("expect 2 network policies, got %d", len(policies.Items))
+	}
+	for _, policy := range policies.Items {
+		if policy.Name == "default-deny" && policy.Spec.PolicyTypes[0] != networkingv1.PolicyTypeIngress {
+			t.Errorf("unexpected policy %v", policy.Spec)
+		}
+	}
+}
+
+func TestNetworkPolicyEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	policy := NewNetworkPolicy(nil).LinkClient(client).Metadata("isolation", "tenant-a").DenyAllIngress()
+	if err := policy.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !policy.Equal(nil) {
+		t.Error("expect equal to the created policy")
+	}
+	other := NewNetworkPolicy(nil).LinkClient(client).Metadata("isolation", "tenant-a").
+		DenyAllIngress().AllowDNSEgress()
+	if other.Equal(nil) {
+		t.Error("expect not equal with another egress")
+	}
+}
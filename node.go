@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type Node struct {
@@ -41,10 +42,16 @@ func NewNode(ctx context.Context) *Node {
 	}
 }
 
-func (n *Node) Link(region, config string) *Node {
+func (n *Node) Link(region, config string, opts ...ClientOption) *Node {
 	n.Region = region
 	n.Config = config
-	n.client = NewKubeClient(region, config)
+	n.client, n.err = NewKubeClient(region, config, opts...)
+	return n
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (n *Node) LinkClient(client kubernetes.Interface) *Node {
+	n.client, n.err = NewKubeClientFor(client), nil
 	return n
 }
 
@@ -74,27 +81,42 @@ func (n *Node) Annotations(annotations map[string]string) *Node {
 }
 
 func (n *Node) Create() error {
+	if n.err != nil {
+		return n.err
+	}
 	Nodes := n.client.CoreV1().Nodes()
 	_, err := Nodes.Create(n.ctx, n.Node, metav1.CreateOptions{})
 	return err
 }
 
 func (n *Node) Delete() error {
+	if n.err != nil {
+		return n.err
+	}
 	Nodes := n.client.CoreV1().Nodes()
 	return Nodes.Delete(n.ctx, n.Name, metav1.DeleteOptions{})
 }
 
 func (n *Node) Update() error {
+	if n.err != nil {
+		return n.err
+	}
 	Nodes := n.client.CoreV1().Nodes()
 	_, err := Nodes.Update(n.ctx, n.Node, metav1.UpdateOptions{})
 	return err
 }
 
 func (n *Node) Get() (*v1.Node, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
 	return n.client.CoreV1().Nodes().Get(n.ctx, n.Name, metav1.GetOptions{})
 }
 
 func (n *Node) Fetch() (*Node, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
 	node, err := n.client.CoreV1().Nodes().Get(n.ctx, n.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -104,10 +126,16 @@ func (n *Node) Fetch() (*Node, error) {
 }
 
 func (n *Node) List() (*v1.NodeList, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
 	return n.client.CoreV1().Nodes().List(n.ctx, metav1.ListOptions{})
 }
 
 func (n *Node) ListPods() ([]v1.Pod, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
 	podList, err := n.client.CoreV1().Pods("").List(n.ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -122,6 +150,9 @@ func (n *Node) ListPods() ([]v1.Pod, error) {
 }
 
 func (n *Node) CreateOrUpdate() error {
+	if n.err != nil {
+		return n.err
+	}
 	_, err := n.client.CoreV1().Nodes().Get(n.ctx, n.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -133,11 +164,17 @@ func (n *Node) CreateOrUpdate() error {
 }
 
 func (n *Node) Empty() bool {
+	if n.err != nil {
+		return false
+	}
 	_, err := n.client.CoreV1().Nodes().Get(n.ctx, n.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (n *Node) Equal(keys []string) bool {
+	if n.err != nil {
+		return false
+	}
 	secret, err := n.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -0,0 +1,151 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/03 10:12:40
+ Desc     : options of the kube client
+*/
+
+package kube
+
+import (
+	"reflect"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// ClientOption configures the rest config of a region's client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	qps         float32
+	burst       int
+	timeout     time.Duration
+	userAgent   string
+	rateLimiter flowcontrol.RateLimiter
+	context     string
+	namespace   string
+	registry    *ClientRegistry
+}
+
+func newClientOptions(opts ...ClientOption) clientOptions {
+	o := clientOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithQPS sets the maximum queries per second to the api server, client-go
+// defaults to 5.
+func WithQPS(qps float32) ClientOption {
+	return func(o *clientOptions) {
+		o.qps = qps
+	}
+}
+
+// WithBurst sets the maximum burst for throttle, client-go defaults to 10.
+func WithBurst(burst int) ClientOption {
+	return func(o *clientOptions) {
+		o.burst = burst
+	}
+}
+
+// WithTimeout sets the timeout of every request to the api server.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the user agent sent to the api server.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithRateLimiter sets a custom rate limiter, QPS and Burst are ignored
+// when it is set.
+func WithRateLimiter(limiter flowcontrol.RateLimiter) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithContext chooses the named context of the kubeconfig instead of the
+// current context.
+func WithContext(context string) ClientOption {
+	return func(o *clientOptions) {
+		o.context = context
+	}
+}
+
+// WithNamespace overrides the namespace of the kubeconfig context.
+func WithNamespace(namespace string) ClientOption {
+	return func(o *clientOptions) {
+		o.namespace = namespace
+	}
+}
+
+// WithRegistry takes the client from the registry instead of the default
+// registry.
+func WithRegistry(registry *ClientRegistry) ClientOption {
+	return func(o *clientOptions) {
+		o.registry = registry
+	}
+}
+
+// empty reports whether no option of the client config is given.
+func (o clientOptions) empty() bool {
+	return o.equal(clientOptions{})
+}
+
+// equal compares the options of the client config, the registry is ignored.
+func (o clientOptions) equal(other clientOptions) bool {
+	return o.qps == other.qps &&
+		o.burst == other.burst &&
+		o.timeout == other.timeout &&
+		o.userAgent == other.userAgent &&
+		sameRateLimiter(o.rateLimiter, other.rateLimiter) &&
+		o.context == other.context &&
+		o.namespace == other.namespace
+}
+
+// sameRateLimiter compares the limiters by identity, a limiter which is not a
+// pointer is compared by value as it can not be shared by the clients.
+func sameRateLimiter(a, b flowcontrol.RateLimiter) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	if va.Kind() == reflect.Ptr {
+		return va.Pointer() == vb.Pointer()
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (o clientOptions) apply(cfg *rest.Config) {
+	if o.qps > 0 {
+		cfg.QPS = o.qps
+	}
+	if o.burst > 0 {
+		cfg.Burst = o.burst
+	}
+	if o.timeout > 0 {
+		cfg.Timeout = o.timeout
+	}
+	if o.userAgent != "" {
+		cfg.UserAgent = o.userAgent
+	}
+	if o.rateLimiter != nil {
+		cfg.RateLimiter = o.rateLimiter
+	}
+}
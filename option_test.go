@@ -0,0 +1,89 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/03 16:48:05
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// valueLimiter is a rate limiter which is not a pointer, the slice makes it
+// incomparable with ==.
+type valueLimiter struct {
+	buckets []int
+}
+
+func (valueLimiter) TryAccept() bool                { return true }
+func (valueLimiter) Accept()                        {}
+func (valueLimiter) Stop()                          {}
+func (valueLimiter) QPS() float32                   { return 0 }
+func (valueLimiter) Wait(ctx context.Context) error { return nil }
+
+func TestClientOptions(t *testing.T) {
+	limiter := flowcontrol.NewTokenBucketRateLimiter(20, 40)
+	cfg, err := LoadClusterConfig(testKubeconfig, WithQPS(50), WithBurst(100), WithTimeout(30*time.Second),
+		WithUserAgent("jeeves/1.0"), WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+	if cfg.QPS != 50 || cfg.Burst != 100 || cfg.Timeout != 30*time.Second || cfg.UserAgent != "jeeves/1.0" {
+		t.Errorf("unexpected qps %v, burst %d, timeout %s or user agent %s", cfg.QPS, cfg.Burst, cfg.Timeout, cfg.UserAgent)
+	}
+	if cfg.RateLimiter != limiter {
+		t.Error("expect the rate limiter to be set")
+	}
+}
+
+func TestClientOptionsCache(t *testing.T) {
+	registry := NewClientRegistry()
+	client, err := registry.Client("dev", testKubeconfig, WithQPS(50), WithBurst(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.RESTConfig().QPS != 50 {
+		t.Errorf("unexpected qps %v", client.RESTConfig().QPS)
+	}
+	// the same options or no options reuse the client
+	if cached, _ := registry.Client("dev", testKubeconfig, WithQPS(50), WithBurst(100)); cached != client {
+		t.Error("expect the cached client with the same options")
+	}
+	if cached, _ := registry.Client("dev", testKubeconfig); cached != client {
+		t.Error("expect the cached client without options")
+	}
+	// other options rebuild it
+	rebuilt, err := registry.Client("dev", testKubeconfig, WithQPS(80), WithBurst(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt == client || rebuilt.RESTConfig().QPS != 80 {
+		t.Error("expect the client to be rebuilt with other options")
+	}
+
+	// pointer limiters are compared by identity
+	limiter := flowcontrol.NewTokenBucketRateLimiter(20, 40)
+	client, _ = registry.Client("dev", testKubeconfig, WithRateLimiter(limiter))
+	if cached, _ := registry.Client("dev", testKubeconfig, WithRateLimiter(limiter)); cached != client {
+		t.Error("expect the cached client with the same limiter")
+	}
+	other := flowcontrol.NewTokenBucketRateLimiter(20, 40)
+	if rebuilt, _ := registry.Client("dev", testKubeconfig, WithRateLimiter(other)); rebuilt == client {
+		t.Error("expect the client to be rebuilt with another limiter")
+	}
+	// limiters which are not pointers are compared by value
+	client, _ = registry.Client("dev", testKubeconfig, WithRateLimiter(valueLimiter{buckets: []int{1}}))
+	if cached, _ := registry.Client("dev", testKubeconfig, WithRateLimiter(valueLimiter{buckets: []int{1}})); cached != client {
+		t.Error("expect the cached client with an equal limiter")
+	}
+	if rebuilt, _ := registry.Client("dev", testKubeconfig, WithRateLimiter(valueLimiter{buckets: []int{2}})); rebuilt == client {
+		t.Error("expect the client to be rebuilt with a different limiter")
+	}
+}
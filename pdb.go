@@ -0,0 +1,193 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/25 10:44:19
+ Desc     : pod disruption budget
+*/
+
+package kube
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PodDisruptionBudget struct {
+	*LinkInfo
+	*policyv1.PodDisruptionBudget
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewPodDisruptionBudget(ctx context.Context) *PodDisruptionBudget {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &PodDisruptionBudget{
+		PodDisruptionBudget: &policyv1.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: "policy/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       policyv1.PodDisruptionBudgetSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (p *PodDisruptionBudget) Link(region, config string, opts ...ClientOption) *PodDisruptionBudget {
+	p.Region = region
+	p.Config = config
+	p.client, p.err = NewKubeClient(region, config, opts...)
+	return p
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (p *PodDisruptionBudget) LinkClient(client kubernetes.Interface) *PodDisruptionBudget {
+	p.client, p.err = NewKubeClientFor(client), nil
+	return p
+}
+
+func (p *PodDisruptionBudget) Metadata(name, namespace string) *PodDisruptionBudget {
+	p.Name, p.Namespace = name, namespace
+	return p
+}
+
+func (p *PodDisruptionBudget) Labels(labels map[string]string) *PodDisruptionBudget {
+	if p.PodDisruptionBudget.Labels == nil {
+		p.PodDisruptionBudget.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		p.PodDisruptionBudget.Labels[k] = v
+	}
+	return p
+}
+
+func (p *PodDisruptionBudget) Annotations(annotations map[string]string) *PodDisruptionBudget {
+	if p.PodDisruptionBudget.Annotations == nil {
+		p.PodDisruptionBudget.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		p.PodDisruptionBudget.Annotations[k] = v
+	}
+	return p
+}
+
+// MinAvailable sets the number or percent ("50%") of pods which must stay
+// available during an eviction, it replaces MaxUnavailable.
+func (p *PodDisruptionBudget) MinAvailable(minAvailable intstr.IntOrString) *PodDisruptionBudget {
+	p.PodDisruptionBudget.Spec.MinAvailable = &minAvailable
+	p.PodDisruptionBudget.Spec.MaxUnavailable = nil
+	return p
+}
+
+// MaxUnavailable sets the number or percent of pods which can be evicted at
+// the same time, it replaces MinAvailable.
+func (p *PodDisruptionBudget) MaxUnavailable(maxUnavailable intstr.IntOrString) *PodDisruptionBudget {
+	p.PodDisruptionBudget.Spec.MaxUnavailable = &maxUnavailable
+	p.PodDisruptionBudget.Spec.MinAvailable = nil
+	return p
+}
+
+func (p *PodDisruptionBudget) Selector(selector map[string]string) *PodDisruptionBudget {
+	p.PodDisruptionBudget.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: selector,
+	}
+	return p
+}
+
+// UnhealthyPodEvictionPolicy decides whether the pods which are running but
+// not ready can be evicted even if the budget is exhausted.
+func (p *PodDisruptionBudget) UnhealthyPodEvictionPolicy(policy policyv1.UnhealthyPodEvictionPolicyType) *PodDisruptionBudget {
+	p.PodDisruptionBudget.Spec.UnhealthyPodEvictionPolicy = &policy
+	return p
+}
+
+func (p *PodDisruptionBudget) Create() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Create(p.ctx, p.PodDisruptionBudget, metav1.CreateOptions{})
+	return err
+}
+
+func (p *PodDisruptionBudget) Delete() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Delete(p.ctx, p.Name, metav1.DeleteOptions{})
+}
+
+func (p *PodDisruptionBudget) Update() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Update(p.ctx, p.PodDisruptionBudget, metav1.UpdateOptions{})
+	return err
+}
+
+func (p *PodDisruptionBudget) Get() (*policyv1.PodDisruptionBudget, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+}
+
+func (p *PodDisruptionBudget) Empty() bool {
+	if p.err != nil {
+		return false
+	}
+	_, err := p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (p *PodDisruptionBudget) CreateOrUpdate() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.PolicyV1().PodDisruptionBudgets(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return p.Create()
+		}
+		return err
+	}
+	return p.Update()
+}
+
+func (p *PodDisruptionBudget) Equal(keys []string) bool {
+	if p.err != nil {
+		return false
+	}
+	pdb, err := p.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(p.PodDisruptionBudget, pdb, keys)
+}
+
+// applyWorkloadPDB creates or updates the budget for the pods of a workload,
+// the budget is named after the workload unless named explicitly.
+func applyWorkloadPDB(client *KubeClient, pdb *PodDisruptionBudget, name, namespace string, selector *metav1.LabelSelector) error {
+	if pdb.Name == "" {
+		pdb.Name = name
+	}
+	pdb.Namespace = namespace
+	pdb.PodDisruptionBudget.Spec.Selector = selector.DeepCopy()
+	return pdb.LinkClient(client.Interface).CreateOrUpdate()
+}
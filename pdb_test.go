@@ -0,0 +1,71 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/25 15:36:02
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWithPDB(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).LinkClient(client).Metadata("inference", "default").
+		Selector(map[string]string{"app": "inference"})
+	pdb := NewPodDisruptionBudget(nil).MinAvailable(intstr.FromInt(1)).MaxUnavailable(intstr.FromString("25%")).
+		UnhealthyPodEvictionPolicy(policyv1.AlwaysAllow)
+	if pdb.Spec.MinAvailable != nil {
+		t.Error("expect MaxUnavailable to replace MinAvailable")
+	}
+	if err := deployment.WithPDB(pdb); err != nil {
+		t.Fatal(err)
+	}
+	// update the budget in place
+	if err := deployment.WithPDB(pdb.MaxUnavailable(intstr.FromInt(1))); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewPodDisruptionBudget(nil).LinkClient(client).Metadata("inference", "default").Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Selector.MatchLabels["app"] != "inference" || got.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Errorf("unexpected pdb %v", got.Spec)
+	}
+
+	statefulSet := NewStatefulSet(nil).LinkClient(client).Metadata("etcd", "default")
+	if err := statefulSet.WithPDB(NewPodDisruptionBudget(nil)); err == nil {
+		t.Error("expect error without selector")
+	}
+	statefulSet.Selector(map[string]string{"app": "etcd"})
+	if err := statefulSet.WithPDB(NewPodDisruptionBudget(nil).Metadata("etcd-quorum", "").MinAvailable(intstr.FromInt(2))); err != nil {
+		t.Fatal(err)
+	}
+	if NewPodDisruptionBudget(nil).LinkClient(client).Metadata("etcd-quorum", "default").Empty() {
+		t.Error("expect pdb named explicitly")
+	}
+}
+
+func TestPodDisruptionBudgetEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pdb := NewPodDisruptionBudget(nil).LinkClient(client).Metadata("inference", "default").
+		MinAvailable(intstr.FromInt(1)).Selector(map[string]string{"app": "inference"})
+	if err := pdb.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !pdb.Equal(nil) {
+		t.Error("expect equal to the created budget")
+	}
+	other := NewPodDisruptionBudget(nil).LinkClient(client).Metadata("inference", "default").
+		MinAvailable(intstr.FromInt(2)).Selector(map[string]string{"app": "inference"})
+	if other.Equal(nil) {
+		t.Error("expect not equal with another min available")
+	}
+}
@@ -2,10 +2,14 @@ package kube
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 type PodsGetter interface {
@@ -24,6 +28,8 @@ type Pod struct {
 	*v1.Pod
 	client *KubeClient
 	ctx    context.Context
+	// readyInterval rechecks WaitForReady without an event
+	readyInterval time.Duration
 }
 
 func NewPod(ctx context.Context) *Pod {
@@ -45,10 +51,40 @@ func NewPod(ctx context.Context) *Pod {
 	}
 }
 
-func (p *Pod) Link(region, config string) *Pod {
+func (p *Pod) Link(region, config string, opts ...ClientOption) *Pod {
 	p.Region = region
 	p.Config = config
-	p.client = NewKubeClient(region, config)
+	p.client, p.err = NewKubeClient(region, config, opts...)
+	return p
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (p *Pod) LinkClient(client kubernetes.Interface) *Pod {
+	p.client, p.err = NewKubeClientFor(client), nil
+	return p
+}
+
+// LinkConfig sets the rest config which Exec connects with, e.g. after
+// LinkClient. Without a linked client the clientset is built from the config.
+func (p *Pod) LinkConfig(config *rest.Config) *Pod {
+	if p.err != nil {
+		return p
+	}
+	if config == nil {
+		p.err = fmt.Errorf("rest config of pod %s is nil", p.Name)
+		return p
+	}
+	if p.client == nil {
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			p.err = err
+			return p
+		}
+		p.client = NewKubeClientFor(client)
+	}
+	// the client of the registry is shared, link a copy of it
+	client := *p.client
+	p.client = client.WithRESTConfig(config)
 	return p
 }
 
@@ -111,31 +147,49 @@ func (p *Pod) ImagePullSecrets(secrets []string) *Pod {
 }
 
 func (p *Pod) Create() error {
+	if p.err != nil {
+		return p.err
+	}
 	pods := p.client.CoreV1().Pods(p.Namespace)
 	_, err := pods.Create(p.ctx, p.Pod, metav1.CreateOptions{})
 	return err
 }
 
 func (p Pod) Delete() error {
+	if p.err != nil {
+		return p.err
+	}
 	pods := p.client.CoreV1().Pods(p.Namespace)
 	return pods.Delete(p.ctx, p.Pod.Name, metav1.DeleteOptions{})
 }
 
 func (p *Pod) Update() error {
+	if p.err != nil {
+		return p.err
+	}
 	pods := p.client.CoreV1().Pods(p.Namespace)
 	_, err := pods.Update(p.ctx, p.Pod, metav1.UpdateOptions{})
 	return err
 }
 
 func (p *Pod) Get() (*v1.Pod, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
 	return p.client.CoreV1().Pods(p.Namespace).Get(p.ctx, p.Pod.Name, metav1.GetOptions{})
 }
 
 func (p *Pod) List() (*v1.PodList, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
 	return p.client.CoreV1().Pods("").List(p.ctx, metav1.ListOptions{})
 }
 
 func (p *Pod) Fetch(replace bool) (*Pod, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
 	pod, err := p.client.CoreV1().Pods(p.Namespace).Get(p.ctx, p.Pod.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -145,7 +199,52 @@ func (p *Pod) Fetch(replace bool) (*Pod, error) {
 
 }
 
+// ReadyCheckInterval sets how often WaitForReady rechecks the pod without
+// an event, it defaults to 2s.
+func (p *Pod) ReadyCheckInterval(interval time.Duration) *Pod {
+	p.readyInterval = interval
+	return p
+}
+
+// WaitForReady waits until the pod is Ready, it fails fast with a
+// *ReadyError when a container is in CrashLoopBackOff or ImagePullBackOff or
+// the pod terminated. A nil ctx uses the context of the builder.
+func (p *Pod) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	if p.err != nil {
+		return p.err
+	}
+	if ctx == nil {
+		ctx = p.ctx
+	}
+	pods := p.client.CoreV1().Pods(p.Namespace)
+	return waitForReady(ctx, timeout, p.readyInterval, "Pod", p.Namespace, p.Name, pods.Watch,
+		func(ctx context.Context) (bool, error) {
+			pod, err := pods.Get(ctx, p.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+				return false, &ReadyError{
+					Kind:      "Pod",
+					Namespace: p.Namespace,
+					Name:      p.Name,
+					Reason:    string(pod.Status.Phase),
+					Message:   pod.Status.Message,
+				}
+			}
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == v1.PodReady && cond.Status == v1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, podFailure("Pod", p.Namespace, p.Name, pod)
+		})
+}
+
 func (p *Pod) CreateOrUpdate() error {
+	if p.err != nil {
+		return p.err
+	}
 	_, err := p.client.CoreV1().Pods(p.Namespace).Get(p.ctx, p.Pod.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
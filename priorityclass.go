@@ -0,0 +1,209 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/26 10:18:52
+ Desc     : priority class
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/piaobeizu/kube/base"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PriorityClass struct {
+	*LinkInfo
+	*schedulingv1.PriorityClass
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewPriorityClass(ctx context.Context) *PriorityClass {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &PriorityClass{
+		PriorityClass: &schedulingv1.PriorityClass{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PriorityClass",
+				APIVersion: "scheduling.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (p *PriorityClass) Link(region, config string, opts ...ClientOption) *PriorityClass {
+	p.Region = region
+	p.Config = config
+	p.client, p.err = NewKubeClient(region, config, opts...)
+	return p
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (p *PriorityClass) LinkClient(client kubernetes.Interface) *PriorityClass {
+	p.client, p.err = NewKubeClientFor(client), nil
+	return p
+}
+
+func (p *PriorityClass) Metadata(name string) *PriorityClass {
+	p.Name = name
+	return p
+}
+
+func (p *PriorityClass) Labels(labels map[string]string) *PriorityClass {
+	if p.PriorityClass.Labels == nil {
+		p.PriorityClass.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		p.PriorityClass.Labels[k] = v
+	}
+	return p
+}
+
+func (p *PriorityClass) Annotations(annotations map[string]string) *PriorityClass {
+	if p.PriorityClass.Annotations == nil {
+		p.PriorityClass.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		p.PriorityClass.Annotations[k] = v
+	}
+	return p
+}
+
+// Value sets the priority, the higher value the pods are scheduled and kept
+// first. The value of an existing priority class can not be changed.
+func (p *PriorityClass) Value(value int32) *PriorityClass {
+	p.PriorityClass.Value = value
+	return p
+}
+
+func (p *PriorityClass) GlobalDefault(globalDefault bool) *PriorityClass {
+	p.PriorityClass.GlobalDefault = globalDefault
+	return p
+}
+
+func (p *PriorityClass) PreemptionPolicy(policy v1.PreemptionPolicy) *PriorityClass {
+	p.PriorityClass.PreemptionPolicy = &policy
+	return p
+}
+
+func (p *PriorityClass) Description(description string) *PriorityClass {
+	p.PriorityClass.Description = description
+	return p
+}
+
+func (p *PriorityClass) Create() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.SchedulingV1().PriorityClasses().Create(p.ctx, p.PriorityClass, metav1.CreateOptions{})
+	return err
+}
+
+func (p *PriorityClass) Delete() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.client.SchedulingV1().PriorityClasses().Delete(p.ctx, p.Name, metav1.DeleteOptions{})
+}
+
+func (p *PriorityClass) Update() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.SchedulingV1().PriorityClasses().Update(p.ctx, p.PriorityClass, metav1.UpdateOptions{})
+	return err
+}
+
+func (p *PriorityClass) Get() (*schedulingv1.PriorityClass, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.SchedulingV1().PriorityClasses().Get(p.ctx, p.Name, metav1.GetOptions{})
+}
+
+func (p *PriorityClass) List() (*schedulingv1.PriorityClassList, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.SchedulingV1().PriorityClasses().List(p.ctx, metav1.ListOptions{})
+}
+
+func (p *PriorityClass) Empty() bool {
+	if p.err != nil {
+		return false
+	}
+	_, err := p.client.SchedulingV1().PriorityClasses().Get(p.ctx, p.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (p *PriorityClass) CreateOrUpdate() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.SchedulingV1().PriorityClasses().Get(p.ctx, p.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return p.Create()
+		}
+		return err
+	}
+	return p.Update()
+}
+
+func (p *PriorityClass) Equal(keys []string) bool {
+	if p.err != nil {
+		return false
+	}
+	priorityClass, err := p.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Value", "GlobalDefault", "PreemptionPolicy", "Description"}
+	}
+	return ResourceEqual(p.PriorityClass, priorityClass, keys)
+}
+
+// EnsurePriorityClasses makes sure the priority classes referenced by the
+// scheduling strategies exist in the region of the client. The missing ones
+// with a value in values are created, the others are returned as missing, so
+// a nil values only verifies the region.
+func EnsurePriorityClasses(ctx context.Context, client *KubeClient, values map[string]int32) (missing []string, err error) {
+	for _, name := range base.PriorityClasses() {
+		pc := NewPriorityClass(ctx).LinkClient(client.Interface).Metadata(name)
+		_, err := pc.Get()
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return missing, fmt.Errorf("get priority class %s error: %w", name, err)
+		}
+		value, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		pc.Value(value).Description("priority class of the jeeves scheduling strategies")
+		if err := pc.Create(); err != nil && !errors.IsAlreadyExists(err) {
+			return missing, fmt.Errorf("create priority class %s error: %w", name, err)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,51 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/26 14:51:30
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/piaobeizu/kube/base"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsurePriorityClasses(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	if err := NewPriorityClass(nil).LinkClient(client).Metadata(base.CPUPriorityClass).Value(100).Create(); err != nil {
+		t.Fatal(err)
+	}
+	kc := NewKubeClientFor(client)
+
+	// verify only
+	missing, err := EnsurePriorityClasses(context.TODO(), kc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{base.GPUPriorityClass, base.HPCPriorityClass, base.A800PriorityClass}
+	if !reflect.DeepEqual(missing, expect) {
+		t.Errorf("expect missing %v, got %v", expect, missing)
+	}
+
+	missing, err = EnsurePriorityClasses(context.TODO(), kc, map[string]int32{
+		base.GPUPriorityClass: 1000,
+		base.HPCPriorityClass: 2000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(missing, []string{base.A800PriorityClass}) {
+		t.Errorf("expect missing %s, got %v", base.A800PriorityClass, missing)
+	}
+	pc, err := NewPriorityClass(nil).LinkClient(client).Metadata(base.HPCPriorityClass).Get()
+	if err != nil || pc.Value != 2000 {
+		t.Errorf("unexpected priority class %v, err: %v", pc, err)
+	}
+}
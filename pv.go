@@ -0,0 +1,256 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/17 14:05:51
+ Desc     : persistent volume
+*/
+
+package kube
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PersistentVolume struct {
+	*LinkInfo
+	*v1.PersistentVolume
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewPersistentVolume(ctx context.Context) *PersistentVolume {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &PersistentVolume{
+		PersistentVolume: &v1.PersistentVolume{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PersistentVolume",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       v1.PersistentVolumeSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (p *PersistentVolume) Link(region, config string, opts ...ClientOption) *PersistentVolume {
+	p.Region = region
+	p.Config = config
+	p.client, p.err = NewKubeClient(region, config, opts...)
+	return p
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (p *PersistentVolume) LinkClient(client kubernetes.Interface) *PersistentVolume {
+	p.client, p.err = NewKubeClientFor(client), nil
+	return p
+}
+
+func (p *PersistentVolume) Metadata(name string) *PersistentVolume {
+	p.Name = name
+	return p
+}
+
+func (p *PersistentVolume) Labels(labels map[string]string) *PersistentVolume {
+	if p.PersistentVolume.Labels == nil {
+		p.PersistentVolume.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		p.PersistentVolume.Labels[k] = v
+	}
+	return p
+}
+
+func (p *PersistentVolume) Annotations(annotations map[string]string) *PersistentVolume {
+	if p.PersistentVolume.Annotations == nil {
+		p.PersistentVolume.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		p.PersistentVolume.Annotations[k] = v
+	}
+	return p
+}
+
+func (p *PersistentVolume) Capacity(size resource.Quantity) *PersistentVolume {
+	p.PersistentVolume.Spec.Capacity = v1.ResourceList{
+		v1.ResourceStorage: size,
+	}
+	return p
+}
+
+func (p *PersistentVolume) AccessModes(modes []v1.PersistentVolumeAccessMode) *PersistentVolume {
+	p.PersistentVolume.Spec.AccessModes = modes
+	return p
+}
+
+func (p *PersistentVolume) StorageClass(storageClass string) *PersistentVolume {
+	p.PersistentVolume.Spec.StorageClassName = storageClass
+	return p
+}
+
+func (p *PersistentVolume) VolumeMode(mode v1.PersistentVolumeMode) *PersistentVolume {
+	p.PersistentVolume.Spec.VolumeMode = &mode
+	return p
+}
+
+func (p *PersistentVolume) ReclaimPolicy(policy v1.PersistentVolumeReclaimPolicy) *PersistentVolume {
+	p.PersistentVolume.Spec.PersistentVolumeReclaimPolicy = policy
+	return p
+}
+
+func (p *PersistentVolume) MountOptions(options []string) *PersistentVolume {
+	p.PersistentVolume.Spec.MountOptions = options
+	return p
+}
+
+// ClaimRef reserves the volume for the named claim.
+func (p *PersistentVolume) ClaimRef(name, namespace string) *PersistentVolume {
+	p.PersistentVolume.Spec.ClaimRef = &v1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Name:       name,
+		Namespace:  namespace,
+	}
+	return p
+}
+
+func (p *PersistentVolume) Source(source v1.PersistentVolumeSource) *PersistentVolume {
+	p.PersistentVolume.Spec.PersistentVolumeSource = source
+	return p
+}
+
+func (p *PersistentVolume) NFS(server, path string, readOnly bool) *PersistentVolume {
+	p.PersistentVolume.Spec.PersistentVolumeSource = v1.PersistentVolumeSource{
+		NFS: &v1.NFSVolumeSource{
+			Server:   server,
+			Path:     path,
+			ReadOnly: readOnly,
+		},
+	}
+	return p
+}
+
+func (p *PersistentVolume) HostPath(path string, t v1.HostPathType) *PersistentVolume {
+	p.PersistentVolume.Spec.PersistentVolumeSource = v1.PersistentVolumeSource{
+		HostPath: &v1.HostPathVolumeSource{
+			Path: path,
+			Type: &t,
+		},
+	}
+	return p
+}
+
+func (p *PersistentVolume) CSI(driver, volumeHandle string, attributes map[string]string) *PersistentVolume {
+	p.PersistentVolume.Spec.PersistentVolumeSource = v1.PersistentVolumeSource{
+		CSI: &v1.CSIPersistentVolumeSource{
+			Driver:           driver,
+			VolumeHandle:     volumeHandle,
+			VolumeAttributes: attributes,
+		},
+	}
+	return p
+}
+
+func (p *PersistentVolume) Create() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.CoreV1().PersistentVolumes().Create(p.ctx, p.PersistentVolume, metav1.CreateOptions{})
+	return err
+}
+
+func (p *PersistentVolume) Delete() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.client.CoreV1().PersistentVolumes().Delete(p.ctx, p.Name, metav1.DeleteOptions{})
+}
+
+func (p *PersistentVolume) Update() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.CoreV1().PersistentVolumes().Update(p.ctx, p.PersistentVolume, metav1.UpdateOptions{})
+	return err
+}
+
+func (p *PersistentVolume) Get() (*v1.PersistentVolume, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.CoreV1().PersistentVolumes().Get(p.ctx, p.Name, metav1.GetOptions{})
+}
+
+func (p *PersistentVolume) List() (*v1.PersistentVolumeList, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.CoreV1().PersistentVolumes().List(p.ctx, metav1.ListOptions{})
+}
+
+func (p *PersistentVolume) Empty() bool {
+	if p.err != nil {
+		return false
+	}
+	_, err := p.client.CoreV1().PersistentVolumes().Get(p.ctx, p.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (p *PersistentVolume) CreateOrUpdate() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.CoreV1().PersistentVolumes().Get(p.ctx, p.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return p.Create()
+		}
+		return err
+	}
+	return p.Update()
+}
+
+func (p *PersistentVolume) Equal(keys []string) bool {
+	if p.err != nil {
+		return false
+	}
+	pv, err := p.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(p.PersistentVolume, pv, keys)
+}
+
+// WaitForBound waits until the volume is bound to a claim.
+func (p *PersistentVolume) WaitForBound(timeout time.Duration) error {
+	if p.err != nil {
+		return p.err
+	}
+	return wait.PollUntilContextTimeout(p.ctx, time.Second, timeout, true,
+		func(ctx context.Context) (bool, error) {
+			pv, err := p.client.CoreV1().PersistentVolumes().Get(ctx, p.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return pv.Status.Phase == v1.VolumeBound, nil
+		})
+}
@@ -0,0 +1,230 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/17 10:32:08
+ Desc     : persistent volume claim
+*/
+
+package kube
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PersistentVolumeClaim struct {
+	*LinkInfo
+	*v1.PersistentVolumeClaim
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewPersistentVolumeClaim(ctx context.Context) *PersistentVolumeClaim {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &PersistentVolumeClaim{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PersistentVolumeClaim",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       v1.PersistentVolumeClaimSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (p *PersistentVolumeClaim) Link(region, config string, opts ...ClientOption) *PersistentVolumeClaim {
+	p.Region = region
+	p.Config = config
+	p.client, p.err = NewKubeClient(region, config, opts...)
+	return p
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (p *PersistentVolumeClaim) LinkClient(client kubernetes.Interface) *PersistentVolumeClaim {
+	p.client, p.err = NewKubeClientFor(client), nil
+	return p
+}
+
+func (p *PersistentVolumeClaim) Metadata(name, namespace string) *PersistentVolumeClaim {
+	p.Name, p.Namespace = name, namespace
+	return p
+}
+
+func (p *PersistentVolumeClaim) Labels(labels map[string]string) *PersistentVolumeClaim {
+	if p.PersistentVolumeClaim.Labels == nil {
+		p.PersistentVolumeClaim.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		p.PersistentVolumeClaim.Labels[k] = v
+	}
+	return p
+}
+
+func (p *PersistentVolumeClaim) Annotations(annotations map[string]string) *PersistentVolumeClaim {
+	if p.PersistentVolumeClaim.Annotations == nil {
+		p.PersistentVolumeClaim.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		p.PersistentVolumeClaim.Annotations[k] = v
+	}
+	return p
+}
+
+func (p *PersistentVolumeClaim) StorageClass(storageClass string) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.StorageClassName = &storageClass
+	return p
+}
+
+func (p *PersistentVolumeClaim) AccessModes(modes []v1.PersistentVolumeAccessMode) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.AccessModes = modes
+	return p
+}
+
+func (p *PersistentVolumeClaim) Size(size resource.Quantity) *PersistentVolumeClaim {
+	if p.PersistentVolumeClaim.Spec.Resources.Requests == nil {
+		p.PersistentVolumeClaim.Spec.Resources.Requests = make(v1.ResourceList)
+	}
+	p.PersistentVolumeClaim.Spec.Resources.Requests[v1.ResourceStorage] = size
+	return p
+}
+
+// Selector limits the persistent volumes which can be bound by labels.
+func (p *PersistentVolumeClaim) Selector(labels map[string]string) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: labels,
+	}
+	return p
+}
+
+func (p *PersistentVolumeClaim) VolumeMode(mode v1.PersistentVolumeMode) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.VolumeMode = &mode
+	return p
+}
+
+// VolumeName binds the claim to the named persistent volume.
+func (p *PersistentVolumeClaim) VolumeName(volumeName string) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.VolumeName = volumeName
+	return p
+}
+
+// DataSource populates the volume from an existing claim (kind
+// PersistentVolumeClaim, empty apiGroup) or a snapshot (kind VolumeSnapshot,
+// apiGroup snapshot.storage.k8s.io).
+func (p *PersistentVolumeClaim) DataSource(kind, apiGroup, name string) *PersistentVolumeClaim {
+	p.PersistentVolumeClaim.Spec.DataSource = &v1.TypedLocalObjectReference{
+		Kind: kind,
+		Name: name,
+	}
+	if apiGroup != "" {
+		p.PersistentVolumeClaim.Spec.DataSource.APIGroup = &apiGroup
+	}
+	return p
+}
+
+func (p *PersistentVolumeClaim) Create() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Create(p.ctx, p.PersistentVolumeClaim, metav1.CreateOptions{})
+	return err
+}
+
+func (p *PersistentVolumeClaim) Delete() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Delete(p.ctx, p.Name, metav1.DeleteOptions{})
+}
+
+// Update changes the labels, annotations and requests of the claim in the
+// cluster. The rest of the spec is immutable and the volume name is set by
+// the binder, so they are left as they are.
+func (p *PersistentVolumeClaim) Update() error {
+	pvc, err := p.Get()
+	if err != nil {
+		return err
+	}
+	pvc.Labels = mergeStringMap(pvc.Labels, p.PersistentVolumeClaim.Labels)
+	pvc.Annotations = mergeStringMap(pvc.Annotations, p.PersistentVolumeClaim.Annotations)
+	if len(p.PersistentVolumeClaim.Spec.Resources.Requests) > 0 {
+		pvc.Spec.Resources.Requests = p.PersistentVolumeClaim.Spec.Resources.Requests.DeepCopy()
+	}
+	_, err = p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Update(p.ctx, pvc, metav1.UpdateOptions{})
+	return err
+}
+
+func (p *PersistentVolumeClaim) Get() (*v1.PersistentVolumeClaim, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+}
+
+func (p *PersistentVolumeClaim) Empty() bool {
+	if p.err != nil {
+		return false
+	}
+	_, err := p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (p *PersistentVolumeClaim) CreateOrUpdate() error {
+	if p.err != nil {
+		return p.err
+	}
+	_, err := p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Get(p.ctx, p.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return p.Create()
+		}
+		return err
+	}
+	return p.Update()
+}
+
+func (p *PersistentVolumeClaim) Equal(keys []string) bool {
+	if p.err != nil {
+		return false
+	}
+	pvc, err := p.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec.AccessModes", "Spec.Resources", "Spec.StorageClassName", "Spec.VolumeMode"}
+	}
+	return ResourceEqual(p.PersistentVolumeClaim, pvc, keys)
+}
+
+// WaitForBound waits until the claim is bound to a volume, claims of a
+// WaitForFirstConsumer storage class are only bound after a pod uses them.
+func (p *PersistentVolumeClaim) WaitForBound(timeout time.Duration) error {
+	if p.err != nil {
+		return p.err
+	}
+	return wait.PollUntilContextTimeout(p.ctx, time.Second, timeout, true,
+		func(ctx context.Context) (bool, error) {
+			pvc, err := p.client.CoreV1().PersistentVolumeClaims(p.Namespace).Get(ctx, p.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return pvc.Status.Phase == v1.ClaimBound, nil
+		})
+}
@@ -0,0 +1,127 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/17 16:40:29
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPersistentVolumeClaimWaitForBound(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pvc := NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadOnlyMany}).Size(resource.MustParse("1Ti")).
+		DataSource("VolumeSnapshot", "snapshot.storage.k8s.io", "dataset-v1")
+	if err := pvc.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pvc.WaitForBound(10 * time.Millisecond); err == nil {
+		t.Fatal("expect timeout of a pending claim")
+	}
+
+	got, err := pvc.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Status.Phase = v1.ClaimBound
+	if _, err := client.CoreV1().PersistentVolumeClaims("default").UpdateStatus(pvc.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pvc.WaitForBound(time.Second); err != nil {
+		t.Errorf("expect claim bound, got %v", err)
+	}
+
+	volume := NewVolume(nil).Metadata("dataset").PersistentVolumeClaim("dataset", true)
+	if source := volume.VolumeSource.PersistentVolumeClaim; source == nil || source.ClaimName != "dataset" || !source.ReadOnly {
+		t.Errorf("unexpected volume source %v", volume.VolumeSource)
+	}
+}
+
+func TestPersistentVolumeClaimEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pvc := NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadOnlyMany}).Size(resource.MustParse("1Ti"))
+	if err := pvc.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !pvc.Equal(nil) {
+		t.Error("expect equal to the created claim")
+	}
+	other := NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}).Size(resource.MustParse("1Ti"))
+	if other.Equal(nil) {
+		t.Error("expect not equal with other access modes")
+	}
+	other = NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadOnlyMany}).Size(resource.MustParse("2Ti"))
+	if other.Equal(nil) {
+		t.Error("expect not equal with another size")
+	}
+}
+
+func TestPersistentVolumeEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pv := NewPersistentVolume(nil).LinkClient(client).Metadata("dataset").
+		Capacity(resource.MustParse("1Ti")).NFS("nfs.example.com", "/datasets", true)
+	if err := pv.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !pv.Equal(nil) {
+		t.Error("expect equal to the created volume")
+	}
+	other := NewPersistentVolume(nil).LinkClient(client).Metadata("dataset").
+		Capacity(resource.MustParse("1Ti")).NFS("nfs.example.com", "/models", true)
+	if other.Equal(nil) {
+		t.Error("expect not equal with another nfs path")
+	}
+}
+
+func TestPersistentVolumeClaimUpdate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pvc := NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}).Size(resource.MustParse("10Gi"))
+	if err := pvc.Create(); err != nil {
+		t.Fatal(err)
+	}
+	// the binder sets the volume and its annotation
+	got, err := pvc.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Spec.VolumeName = "pv-dataset"
+	got.Annotations = map[string]string{"pv.kubernetes.io/bind-completed": "yes"}
+	if _, err := client.CoreV1().PersistentVolumeClaims("default").Update(pvc.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expanded := NewPersistentVolumeClaim(nil).LinkClient(client).Metadata("dataset", "default").
+		Labels(map[string]string{"app": "train"}).
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}).Size(resource.MustParse("20Gi"))
+	if err := expanded.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = pvc.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.VolumeName != "pv-dataset" || got.Annotations["pv.kubernetes.io/bind-completed"] != "yes" {
+		t.Errorf("expect the binding kept, got volume %q and annotations %v", got.Spec.VolumeName, got.Annotations)
+	}
+	if size := got.Spec.Resources.Requests[v1.ResourceStorage]; size.String() != "20Gi" {
+		t.Errorf("expect 20Gi requested, got %s", size.String())
+	}
+	if got.Labels["app"] != "train" {
+		t.Errorf("expect the labels updated, got %v", got.Labels)
+	}
+}
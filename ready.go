@@ -0,0 +1,171 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/01 10:08:33
+ Desc     : wait for the workloads to be ready
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	ReasonCrashLoopBackOff         = "CrashLoopBackOff"
+	ReasonImagePullBackOff         = "ImagePullBackOff"
+	ReasonTimeout                  = "Timeout"
+)
+
+// defaultReadyCheckInterval rechecks the workload without an event, the pods
+// of a workload may fail while the workload itself does not change.
+const defaultReadyCheckInterval = 2 * time.Second
+
+// ReadyError tells why WaitForReady gave up on a workload.
+type ReadyError struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason is one of the Reason* constants, or the phase of a pod which
+	// terminated
+	Reason string
+	// Pod and Container are set when a container of a pod failed
+	Pod       string
+	Container string
+	Message   string
+	err       error
+}
+
+func (e *ReadyError) Error() string {
+	msg := fmt.Sprintf("%s %s/%s is not ready: %s", e.Kind, e.Namespace, e.Name, e.Reason)
+	if e.Pod != "" {
+		msg += fmt.Sprintf(" in container %s of pod %s", e.Container, e.Pod)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *ReadyError) Unwrap() error {
+	return e.err
+}
+
+// waitForReady calls check on every event of the watch and every interval,
+// defaultReadyCheckInterval if 0, until it is done, fails or the timeout
+// expires.
+func waitForReady(ctx context.Context, timeout, interval time.Duration, kind, namespace, name string,
+	watchFn func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error),
+	check func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	opts := metav1.ListOptions{FieldSelector: "metadata.name=" + name}
+	// without a watch the workload is still checked by the ticker
+	var (
+		w      watch.Interface
+		events <-chan watch.Event
+	)
+	rewatch := func() {
+		if w != nil {
+			w.Stop()
+		}
+		w, events = nil, nil
+		if nw, err := watchFn(ctx, opts); err == nil {
+			w, events = nw, nw.ResultChan()
+		}
+	}
+	rewatch()
+	defer func() {
+		if w != nil {
+			w.Stop()
+		}
+	}()
+	if interval <= 0 {
+		interval = defaultReadyCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	timeoutErr := func() error {
+		return &ReadyError{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Reason:    ReasonTimeout,
+			Message:   fmt.Sprintf("not ready in %s", timeout),
+			err:       ctx.Err(),
+		}
+	}
+	for {
+		done, err := check(ctx)
+		if err != nil && ctx.Err() != nil {
+			return timeoutErr()
+		}
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return timeoutErr()
+		case _, ok := <-events:
+			if !ok {
+				// the watch is closed by the server
+				rewatch()
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func selectPods(ctx context.Context, client *KubeClient, namespace string, selector *metav1.LabelSelector) ([]v1.Pod, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: s.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// podsFailure returns the error of the first pod with a container in
+// CrashLoopBackOff or ImagePullBackOff.
+func podsFailure(kind, namespace, name string, pods []v1.Pod) error {
+	for _, pod := range pods {
+		if err := podFailure(kind, namespace, name, &pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func podFailure(kind, namespace, name string, pod *v1.Pod) error {
+	statuses := append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		switch status.State.Waiting.Reason {
+		case ReasonCrashLoopBackOff, ReasonImagePullBackOff:
+			return &ReadyError{
+				Kind:      kind,
+				Namespace: namespace,
+				Name:      name,
+				Reason:    status.State.Waiting.Reason,
+				Pod:       pod.Name,
+				Container: status.Name,
+				Message:   status.State.Waiting.Message,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/01 15:41:26
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeploymentWaitForReady(t *testing.T) {
+	ctx := context.TODO()
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).LinkClient(client).Metadata("inference", "default").
+		Replicas(2).Selector(map[string]string{"app": "inference"})
+	deployment.Deployment.UID = "uid-inference"
+	if err := deployment.Create(); err != nil {
+		t.Fatal(err)
+	}
+	controller := true
+	for revision, hash := range map[string]string{"1": "old", "2": "new"} {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:        "inference-" + hash,
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "inference", appsv1.DefaultDeploymentUniqueLabelKey: hash},
+			Annotations: map[string]string{RevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "inference", UID: "uid-inference", Controller: &controller,
+			}},
+		}}
+		if _, err := client.AppsV1().ReplicaSets("default").Create(ctx, rs, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	crashingPod := func(name, hash string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+				Labels: map[string]string{"app": "inference", appsv1.DefaultDeploymentUniqueLabelKey: hash}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "server",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: ReasonCrashLoopBackOff, Message: "back-off 5m0s"}},
+			}}},
+		}
+	}
+
+	// becomes ready by an event of the watch
+	deployment.ReadyCheckInterval(time.Hour)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		got, _ := deployment.Get()
+		got.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+		client.AppsV1().Deployments("default").UpdateStatus(ctx, got, metav1.UpdateOptions{})
+	}()
+	if err := deployment.WaitForReady(ctx, 5*time.Second); err != nil {
+		t.Fatalf("expect deployment ready, got %v", err)
+	}
+
+	// a crashing pod of the old replicaset is being replaced
+	got, _ := deployment.Get()
+	got.Status.AvailableReplicas = 1
+	client.AppsV1().Deployments("default").UpdateStatus(ctx, got, metav1.UpdateOptions{})
+	client.CoreV1().Pods("default").Create(ctx, crashingPod("inference-old-abc", "old"), metav1.CreateOptions{})
+	var readyErr *ReadyError
+	err := deployment.WaitForReady(ctx, 100*time.Millisecond)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonTimeout {
+		t.Fatalf("expect timeout with a crashing old pod, got %v", err)
+	}
+
+	// a crashing pod of the new replicaset fails fast
+	client.CoreV1().Pods("default").Create(ctx, crashingPod("inference-new-abc", "new"), metav1.CreateOptions{})
+	err = deployment.WaitForReady(ctx, 5*time.Second)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonCrashLoopBackOff || readyErr.Pod != "inference-new-abc" || readyErr.Container != "server" {
+		t.Fatalf("expect CrashLoopBackOff of pod inference-new-abc, got %v", err)
+	}
+
+	got, _ = deployment.Get()
+	got.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type:   appsv1.DeploymentProgressing,
+		Status: v1.ConditionFalse,
+		Reason: ReasonProgressDeadlineExceeded,
+	}}
+	client.AppsV1().Deployments("default").UpdateStatus(ctx, got, metav1.UpdateOptions{})
+	err = deployment.WaitForReady(ctx, 5*time.Second)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonProgressDeadlineExceeded {
+		t.Fatalf("expect ProgressDeadlineExceeded, got %v", err)
+	}
+}
+
+func TestDaemonSetWaitForReady(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ds := NewDaemonSet(nil).LinkClient(client).Metadata("exporter", "default").
+		Selector(map[string]string{"app": "exporter"})
+	ds.DaemonSet.UID = "uid-exporter"
+	ds.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2}
+	if err := ds.Create(); err != nil {
+		t.Fatal(err)
+	}
+	var readyErr *ReadyError
+	err := ds.WaitForReady(context.TODO(), 50*time.Millisecond)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonTimeout || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect timeout, got %v", err)
+	}
+
+	ctx := context.TODO()
+	controller := true
+	for revision, hash := range map[int64]string{1: "old", 2: "new"} {
+		cr := &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "exporter-" + hash,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "exporter", appsv1.ControllerRevisionHashLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "DaemonSet", Name: "exporter", UID: "uid-exporter", Controller: &controller,
+				}},
+			},
+			Revision: revision,
+		}
+		if _, err := client.AppsV1().ControllerRevisions("default").Create(ctx, cr, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	crashingPod := func(name, hash string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+				Labels: map[string]string{"app": "exporter", appsv1.ControllerRevisionHashLabelKey: hash}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "exporter",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: ReasonCrashLoopBackOff}},
+			}}},
+		}
+	}
+
+	// a crashing pod of the previous revision is being replaced
+	client.CoreV1().Pods("default").Create(ctx, crashingPod("exporter-old-abc", "old"), metav1.CreateOptions{})
+	err = ds.WaitForReady(ctx, 100*time.Millisecond)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonTimeout {
+		t.Fatalf("expect timeout with a crashing old pod, got %v", err)
+	}
+
+	// a crashing pod of the current revision fails fast
+	client.CoreV1().Pods("default").Create(ctx, crashingPod("exporter-new-abc", "new"), metav1.CreateOptions{})
+	err = ds.WaitForReady(ctx, 5*time.Second)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonCrashLoopBackOff || readyErr.Pod != "exporter-new-abc" {
+		t.Fatalf("expect CrashLoopBackOff of pod exporter-new-abc, got %v", err)
+	}
+}
+
+func TestPodWaitForReady(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	pod := NewPod(nil).LinkClient(client).Metadata("notebook", "default")
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	if err := pod.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pod.WaitForReady(context.TODO(), time.Second); err != nil {
+		t.Fatalf("expect pod ready, got %v", err)
+	}
+	// a nil context falls back to the one of the builder
+	if err := pod.WaitForReady(nil, time.Second); err != nil {
+		t.Fatalf("expect pod ready with a nil context, got %v", err)
+	}
+
+	pending := NewPod(nil).LinkClient(client).Metadata("pending", "default")
+	pending.Status.InitContainerStatuses = []v1.ContainerStatus{{
+		Name:  "init",
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: ReasonImagePullBackOff}},
+	}}
+	if err := pending.Create(); err != nil {
+		t.Fatal(err)
+	}
+	var readyErr *ReadyError
+	err := pending.WaitForReady(context.TODO(), time.Second)
+	if !errors.As(err, &readyErr) || readyErr.Reason != ReasonImagePullBackOff || readyErr.Container != "init" {
+		t.Fatalf("expect ImagePullBackOff, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/05 11:20:37
+ Desc     : registry of the multi-region clients
+*/
+
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+var defaultRegistry = NewClientRegistry()
+
+// DefaultRegistry returns the registry used by the builders when no
+// registry is given by WithRegistry.
+func DefaultRegistry() *ClientRegistry {
+	return defaultRegistry
+}
+
+// ClientRegistry caches one client per region, the client is rebuilt when
+// the kubeconfig of the region is rotated.
+type ClientRegistry struct {
+	lock    sync.RWMutex
+	entries map[string]*registryEntry
+}
+
+type registryEntry struct {
+	// key is the hash of the kubeconfig content
+	key        string
+	kubeconfig string
+	options    clientOptions
+	client     *KubeClient
+}
+
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{
+		entries: make(map[string]*registryEntry),
+	}
+}
+
+// Register builds the client of the region and replaces the cached one.
+func (r *ClientRegistry) Register(region, kubeconfig string, opts ...ClientOption) (*KubeClient, error) {
+	entry, err := newRegistryEntry(region, kubeconfig, newClientOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	r.entries[region] = entry
+	r.lock.Unlock()
+	return entry.client, nil
+}
+
+// Client returns the cached client of the region, it is built when the
+// region is not registered, the kubeconfig changed or different options
+// are given.
+func (r *ClientRegistry) Client(region, kubeconfig string, opts ...ClientOption) (*KubeClient, error) {
+	return r.client(region, kubeconfig, newClientOptions(opts...))
+}
+
+func (r *ClientRegistry) client(region, kubeconfig string, options clientOptions) (*KubeClient, error) {
+	key := hashKubeconfig(kubeconfig)
+	r.lock.RLock()
+	entry, ok := r.entries[region]
+	r.lock.RUnlock()
+	if ok && entry.match(key, options) {
+		return entry.client, nil
+	}
+	if ok && options.empty() {
+		// keep the options of the region when none is given
+		options = entry.options
+	}
+	entry, err := newRegistryEntry(region, kubeconfig, options)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	// another goroutine may have registered the same kubeconfig meanwhile
+	if cached, ok := r.entries[region]; ok && cached.match(key, options) {
+		return cached.client, nil
+	}
+	r.entries[region] = entry
+	return entry.client, nil
+}
+
+// Lookup returns the cached client of the region without building it.
+func (r *ClientRegistry) Lookup(region string) (*KubeClient, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	entry, ok := r.entries[region]
+	if !ok {
+		return nil, false
+	}
+	return entry.client, true
+}
+
+// Refresh rebuilds the client of the region from its registered kubeconfig,
+// a kubeconfig file is read again.
+func (r *ClientRegistry) Refresh(region string) (*KubeClient, error) {
+	r.lock.RLock()
+	entry, ok := r.entries[region]
+	r.lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("region %s is not registered", region)
+	}
+	entry, err := newRegistryEntry(region, entry.kubeconfig, entry.options)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	r.entries[region] = entry
+	r.lock.Unlock()
+	return entry.client, nil
+}
+
+// Remove drops the client of the region.
+func (r *ClientRegistry) Remove(region string) {
+	r.lock.Lock()
+	delete(r.entries, region)
+	r.lock.Unlock()
+}
+
+// Regions returns the registered regions in order.
+func (r *ClientRegistry) Regions() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	regions := make([]string, 0, len(r.entries))
+	for region := range r.entries {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+func newRegistryEntry(region, kubeconfig string, options clientOptions) (*registryEntry, error) {
+	cfg, err := loadClusterConfig(kubeconfig, options)
+	if err != nil {
+		return nil, fmt.Errorf("build config of region %s error: %w", region, err)
+	}
+	client, err := kubernetes.NewForConfig(cfg.Config)
+	if err != nil {
+		return nil, fmt.Errorf("create clientset of region %s error: %w", region, err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg.Config)
+	if err != nil {
+		return nil, fmt.Errorf("create dynamic client of region %s error: %w", region, err)
+	}
+	// the mapper discovers the resources lazily on the first lookup
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(client.Discovery()))
+	return &registryEntry{
+		key:        hashKubeconfig(kubeconfig),
+		kubeconfig: kubeconfig,
+		options:    options,
+		client:     NewKubeClientFor(client).WithDynamic(dynamicClient, mapper).WithRESTConfig(cfg.Config),
+	}, nil
+}
+
+func (e *registryEntry) match(key string, options clientOptions) bool {
+	return e.key == key && (options.empty() || e.options.equal(options))
+}
+
+// hashKubeconfig hashes the kubeconfig content, the file is read when
+// kubeconfig is a path so that a rotated file gets a new key.
+func hashKubeconfig(kubeconfig string) string {
+	content := []byte(kubeconfig)
+	if kubeconfig != "" && !isKubeconfigContent(kubeconfig) {
+		if data, err := os.ReadFile(kubeconfig); err == nil {
+			content = append(content, data...)
+		}
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
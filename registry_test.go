@@ -0,0 +1,96 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/05 14:48:19
+ Desc     :
+*/
+
+package kube
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClientRegistry(t *testing.T) {
+	registry := NewClientRegistry()
+	client, err := registry.Client("dev", testKubeconfig)
+	if err != nil {
+		t.Fatalf("get client error: %v", err)
+	}
+	if client.Dynamic() == nil || client.RESTMapper() == nil {
+		t.Error("expect dynamic client of the registered region")
+	}
+	if client.RESTConfig() == nil {
+		t.Error("expect rest config of the registered region")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c, err := registry.Client("dev", testKubeconfig); err != nil || c != client {
+				t.Errorf("expect the cached client, err: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rotated := strings.Replace(testKubeconfig, "secret-token", "rotated-token", 1)
+	rotatedClient, err := registry.Client("dev", rotated)
+	if err != nil {
+		t.Fatalf("get client error: %v", err)
+	}
+	if rotatedClient == client {
+		t.Error("expect a new client for the rotated kubeconfig")
+	}
+
+	refreshed, err := registry.Refresh("dev")
+	if err != nil {
+		t.Fatalf("refresh client error: %v", err)
+	}
+	if refreshed == rotatedClient {
+		t.Error("expect a new client after refresh")
+	}
+	if c, ok := registry.Lookup("dev"); !ok || c != refreshed {
+		t.Error("expect the refreshed client")
+	}
+
+	registry.Remove("dev")
+	if _, ok := registry.Lookup("dev"); ok {
+		t.Error("expect region removed")
+	}
+	if _, err := registry.Refresh("dev"); err == nil {
+		t.Error("expect error of refreshing a removed region")
+	}
+	if _, err := registry.Register("broken", "/not/exist/kubeconfig"); err == nil {
+		t.Error("expect error of broken kubeconfig")
+	}
+	if regions := registry.Regions(); len(regions) != 0 {
+		t.Errorf("unexpected regions %v", regions)
+	}
+}
+
+func TestLinkWithRegistry(t *testing.T) {
+	registry := NewClientRegistry()
+	pod := NewPod(nil).Link("dev", testKubeconfig, WithRegistry(registry))
+	if pod.Err() != nil {
+		t.Fatalf("link error: %v", pod.Err())
+	}
+	if _, ok := registry.Lookup("dev"); !ok {
+		t.Error("expect region registered in the given registry")
+	}
+	if _, ok := DefaultRegistry().Lookup("dev"); ok {
+		t.Error("expect default registry untouched")
+	}
+
+	pod = NewPod(nil).Link("broken", "/not/exist/kubeconfig", WithRegistry(registry))
+	if pod.Err() == nil {
+		t.Fatal("expect link error")
+	}
+	if err := pod.Create(); err != pod.Err() {
+		t.Errorf("expect the link error, got %v", err)
+	}
+}
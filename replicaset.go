@@ -0,0 +1,222 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/03 10:21:46
+ Desc     : replicaset
+*/
+
+package kube
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ReplicaSet struct {
+	*LinkInfo
+	*appsv1.ReplicaSet
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewReplicaSet(ctx context.Context) *ReplicaSet {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &ReplicaSet{
+		ReplicaSet: &appsv1.ReplicaSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ReplicaSet",
+				APIVersion: "apps/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       appsv1.ReplicaSetSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (r *ReplicaSet) Link(region, config string, opts ...ClientOption) *ReplicaSet {
+	r.Region = region
+	r.Config = config
+	r.client, r.err = NewKubeClient(region, config, opts...)
+	return r
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (r *ReplicaSet) LinkClient(client kubernetes.Interface) *ReplicaSet {
+	r.client, r.err = NewKubeClientFor(client), nil
+	return r
+}
+
+func (r *ReplicaSet) Metadata(name, namespace string) *ReplicaSet {
+	r.Name, r.Namespace = name, namespace
+	return r
+}
+
+func (r *ReplicaSet) Labels(labels map[string]string) *ReplicaSet {
+	if r.ReplicaSet.Labels == nil {
+		r.ReplicaSet.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		r.ReplicaSet.Labels[k] = v
+	}
+	return r
+}
+
+func (r *ReplicaSet) Annotations(annotations map[string]string) *ReplicaSet {
+	if r.ReplicaSet.Annotations == nil {
+		r.ReplicaSet.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		r.ReplicaSet.Annotations[k] = v
+	}
+	return r
+}
+
+func (r *ReplicaSet) Replicas(replicas int32) *ReplicaSet {
+	r.ReplicaSet.Spec.Replicas = &replicas
+	return r
+}
+
+func (r *ReplicaSet) Selector(selector map[string]string) *ReplicaSet {
+	r.ReplicaSet.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: selector,
+	}
+	return r
+}
+
+func (r *ReplicaSet) Template(pod *PodTemplate) *ReplicaSet {
+	if pod == nil {
+		return r
+	}
+	r.ReplicaSet.Spec.Template = pod.Template
+	return r
+}
+
+func (r *ReplicaSet) MinReadySeconds(seconds int32) *ReplicaSet {
+	r.ReplicaSet.Spec.MinReadySeconds = seconds
+	return r
+}
+
+func (r *ReplicaSet) Create() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.AppsV1().ReplicaSets(r.Namespace).Create(r.ctx, r.ReplicaSet, metav1.CreateOptions{})
+	return err
+}
+
+func (r *ReplicaSet) Delete() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.client.AppsV1().ReplicaSets(r.Namespace).Delete(r.ctx, r.Name, metav1.DeleteOptions{})
+}
+
+func (r *ReplicaSet) Update() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.AppsV1().ReplicaSets(r.Namespace).Update(r.ctx, r.ReplicaSet, metav1.UpdateOptions{})
+	return err
+}
+
+func (r *ReplicaSet) Get() (*appsv1.ReplicaSet, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.client.AppsV1().ReplicaSets(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+}
+
+func (r *ReplicaSet) Empty() bool {
+	if r.err != nil {
+		return false
+	}
+	_, err := r.client.AppsV1().ReplicaSets(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (r *ReplicaSet) CreateOrUpdate() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.AppsV1().ReplicaSets(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.Create()
+		}
+		return err
+	}
+	return r.Update()
+}
+
+func (r *ReplicaSet) GetReplicas() (int32, error) {
+	rs, err := r.Get()
+	if err != nil {
+		return 0, err
+	}
+	return *rs.Spec.Replicas, nil
+}
+
+// Scale sets the replicas through the scale subresource, the rest of the
+// replicaset is left untouched.
+func (r *ReplicaSet) Scale(replicas int32) error {
+	if r.err != nil {
+		return r.err
+	}
+	if err := scaleTo(r.ctx, r.client.AppsV1().ReplicaSets(r.Namespace), r.Name, replicas); err != nil {
+		return err
+	}
+	r.ReplicaSet.Spec.Replicas = &replicas
+	return nil
+}
+
+// Hibernate scales the replicaset to zero, the replicas are recorded in the
+// HibernatedReplicasAnnotation for Wake.
+func (r *ReplicaSet) Hibernate() error {
+	if r.err != nil {
+		return r.err
+	}
+	return hibernate(r.ctx, r.client.AppsV1().ReplicaSets(r.Namespace), r.Name, r.patch)
+}
+
+// Wake restores the replicas recorded by Hibernate.
+func (r *ReplicaSet) Wake() error {
+	rs, err := r.Get()
+	if err != nil {
+		return err
+	}
+	return wake(r.ctx, r.client.AppsV1().ReplicaSets(r.Namespace), r.Name, rs.Annotations, r.patch)
+}
+
+func (r *ReplicaSet) patch(data []byte) error {
+	_, err := r.client.AppsV1().ReplicaSets(r.Namespace).Patch(r.ctx, r.Name,
+		types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+func (r *ReplicaSet) Equal(keys []string) bool {
+	if r.err != nil {
+		return false
+	}
+	rs, err := r.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(r.ReplicaSet, rs, keys)
+}
@@ -0,0 +1,32 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/03 16:05:52
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReplicaSetEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rs := NewReplicaSet(nil).LinkClient(client).Metadata("notebook", "default").
+		Replicas(2).Selector(map[string]string{"app": "notebook"})
+	if err := rs.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !rs.Equal(nil) {
+		t.Error("expect equal to the created replicaset")
+	}
+	other := NewReplicaSet(nil).LinkClient(client).Metadata("notebook", "default").
+		Replicas(3).Selector(map[string]string{"app": "notebook"})
+	if other.Equal(nil) {
+		t.Error("expect not equal with other replicas")
+	}
+}
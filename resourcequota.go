@@ -0,0 +1,233 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/14 09:47:15
+ Desc     : resource quota
+*/
+
+package kube
+
+import (
+	"context"
+
+	"github.com/piaobeizu/kube/base"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ResourceQuota struct {
+	*LinkInfo
+	*v1.ResourceQuota
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewResourceQuota(ctx context.Context) *ResourceQuota {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &ResourceQuota{
+		ResourceQuota: &v1.ResourceQuota{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ResourceQuota",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       v1.ResourceQuotaSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (q *ResourceQuota) Link(region, config string, opts ...ClientOption) *ResourceQuota {
+	q.Region = region
+	q.Config = config
+	q.client, q.err = NewKubeClient(region, config, opts...)
+	return q
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (q *ResourceQuota) LinkClient(client kubernetes.Interface) *ResourceQuota {
+	q.client, q.err = NewKubeClientFor(client), nil
+	return q
+}
+
+func (q *ResourceQuota) Metadata(name, namespace string) *ResourceQuota {
+	q.Name, q.Namespace = name, namespace
+	return q
+}
+
+func (q *ResourceQuota) Labels(labels map[string]string) *ResourceQuota {
+	if q.ResourceQuota.Labels == nil {
+		q.ResourceQuota.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		q.ResourceQuota.Labels[k] = v
+	}
+	return q
+}
+
+func (q *ResourceQuota) Annotations(annotations map[string]string) *ResourceQuota {
+	if q.ResourceQuota.Annotations == nil {
+		q.ResourceQuota.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		q.ResourceQuota.Annotations[k] = v
+	}
+	return q
+}
+
+// Hard sets the hard limits, extended resources are quoted as requests.
+func (q *ResourceQuota) Hard(hard v1.ResourceList) *ResourceQuota {
+	if q.ResourceQuota.Spec.Hard == nil {
+		q.ResourceQuota.Spec.Hard = make(v1.ResourceList)
+	}
+	for name, quantity := range hard {
+		q.ResourceQuota.Spec.Hard[quotaResourceName(name)] = quantity
+	}
+	return q
+}
+
+// Resource sets the hard limits from the resource with the same names as
+// Resource.ResourceList of the linked region, zero values are left unlimited.
+func (q *ResourceQuota) Resource(resource base.Resource) *ResourceQuota {
+	hard := v1.ResourceList{}
+	for name, quantity := range resource.ResourceList(q.Region) {
+		if !quantity.IsZero() {
+			hard[name] = quantity
+		}
+	}
+	return q.Hard(hard)
+}
+
+func (q *ResourceQuota) Scopes(scopes []v1.ResourceQuotaScope) *ResourceQuota {
+	q.ResourceQuota.Spec.Scopes = scopes
+	return q
+}
+
+func (q *ResourceQuota) Create() error {
+	if q.err != nil {
+		return q.err
+	}
+	_, err := q.client.CoreV1().ResourceQuotas(q.Namespace).Create(q.ctx, q.ResourceQuota, metav1.CreateOptions{})
+	return err
+}
+
+func (q *ResourceQuota) Delete() error {
+	if q.err != nil {
+		return q.err
+	}
+	return q.client.CoreV1().ResourceQuotas(q.Namespace).Delete(q.ctx, q.Name, metav1.DeleteOptions{})
+}
+
+func (q *ResourceQuota) Update() error {
+	if q.err != nil {
+		return q.err
+	}
+	_, err := q.client.CoreV1().ResourceQuotas(q.Namespace).Update(q.ctx, q.ResourceQuota, metav1.UpdateOptions{})
+	return err
+}
+
+func (q *ResourceQuota) Get() (*v1.ResourceQuota, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.client.CoreV1().ResourceQuotas(q.Namespace).Get(q.ctx, q.Name, metav1.GetOptions{})
+}
+
+func (q *ResourceQuota) Empty() bool {
+	if q.err != nil {
+		return false
+	}
+	_, err := q.client.CoreV1().ResourceQuotas(q.Namespace).Get(q.ctx, q.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (q *ResourceQuota) CreateOrUpdate() error {
+	if q.err != nil {
+		return q.err
+	}
+	_, err := q.client.CoreV1().ResourceQuotas(q.Namespace).Get(q.ctx, q.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return q.Create()
+		}
+		return err
+	}
+	return q.Update()
+}
+
+func (q *ResourceQuota) Equal(keys []string) bool {
+	if q.err != nil {
+		return false
+	}
+	quota, err := q.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(q.ResourceQuota, quota, keys)
+}
+
+// Usage returns the used and hard resources of the quota in the cluster.
+func (q *ResourceQuota) Usage() (used base.Resource, hard base.Resource, err error) {
+	quota, err := q.Get()
+	if err != nil {
+		return base.Resource{}, base.Resource{}, err
+	}
+	return base.ConvertResourceListToResource(quota.Status.Used),
+		base.ConvertResourceListToResource(quotaHard(quota)), nil
+}
+
+// Fits reports whether the resource can still be admitted by the quota, the
+// resource is checked against both the requests and the limits quoted, the
+// resources without a hard limit are not checked.
+func (q *ResourceQuota) Fits(resource base.Resource) (bool, error) {
+	quota, err := q.Get()
+	if err != nil {
+		return false, err
+	}
+	hard := quotaHard(quota)
+	for name, quantity := range resource.ResourceList(q.Region) {
+		for _, quoted := range quotedNames(name) {
+			limit, ok := hard[quoted]
+			if !ok {
+				continue
+			}
+			total := quota.Status.Used[quoted].DeepCopy()
+			total.Add(quantity)
+			if total.Cmp(limit) > 0 {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// quotedNames returns the names a resource can be quoted by, cpu, memory and
+// ephemeral-storage can be quoted by the bare name, the requests and the
+// limits, the extended resources by the requests only.
+func quotedNames(name v1.ResourceName) []v1.ResourceName {
+	if quoted := quotaResourceName(name); quoted != name {
+		return []v1.ResourceName{quoted}
+	}
+	return []v1.ResourceName{name, "requests." + name, "limits." + name}
+}
+
+// quotaHard prefers the hard limits enforced by the quota controller.
+func quotaHard(quota *v1.ResourceQuota) v1.ResourceList {
+	if len(quota.Status.Hard) > 0 {
+		return quota.Status.Hard
+	}
+	return quota.Spec.Hard
+}
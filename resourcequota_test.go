@@ -0,0 +1,123 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/14 11:26:53
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"github.com/piaobeizu/kube/base"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResourceQuotaUsage(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	quota := NewResourceQuota(nil).LinkClient(client).Metadata("tenant", "default").
+		Resource(base.Resource{CPUNum: 32, MemorySize: 128, GPUNum: 8})
+	if _, ok := quota.Spec.Hard["requests.nvidia.com/gpu"]; !ok {
+		t.Fatalf("expect gpu quoted as requests, got %v", quota.Spec.Hard)
+	}
+	if _, ok := quota.Spec.Hard[v1.ResourceEphemeralStorage]; ok {
+		t.Fatal("expect zero ephemeral storage left unlimited")
+	}
+	if err := quota.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := quota.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Status.Hard = got.Spec.Hard
+	got.Status.Used = v1.ResourceList{
+		v1.ResourceCPU:            resource.MustParse("30"),
+		v1.ResourceMemory:         resource.MustParse("64Gi"),
+		"requests.nvidia.com/gpu": resource.MustParse("6"),
+	}
+	if _, err := client.CoreV1().ResourceQuotas("default").UpdateStatus(quota.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	used, hard, err := quota.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used.CPUNum != 30 || used.MemorySize != 64 || used.GPUNum != 6 {
+		t.Errorf("unexpected used %+v", used)
+	}
+	if hard.CPUNum != 32 || hard.MemorySize != 128 || hard.GPUNum != 8 {
+		t.Errorf("unexpected hard %+v", hard)
+	}
+
+	if fits, err := quota.Fits(base.Resource{CPUNum: 2, MemorySize: 16, GPUNum: 2}); err != nil || !fits {
+		t.Errorf("expect job fits, err: %v", err)
+	}
+	if fits, err := quota.Fits(base.Resource{CPUNum: 2, MemorySize: 16, GPUNum: 4}); err != nil || fits {
+		t.Errorf("expect job exceeds the gpu quota, err: %v", err)
+	}
+}
+
+func TestResourceQuotaRequestsAndLimits(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	quota := NewResourceQuota(nil).LinkClient(client).Metadata("tenant", "default")
+	quota.ResourceQuota.Spec.Hard = v1.ResourceList{
+		"requests.cpu":    resource.MustParse("16"),
+		"limits.cpu":      resource.MustParse("32"),
+		"limits.memory":   resource.MustParse("64Gi"),
+		"requests.memory": resource.MustParse("32Gi"),
+	}
+	if err := quota.Create(); err != nil {
+		t.Fatal(err)
+	}
+	// the limits never leak into the requests, whatever the map order is
+	for i := 0; i < 20; i++ {
+		_, hard, err := quota.Usage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hard.CPUNum != 16 || hard.MemorySize != 32 {
+			t.Fatalf("expect the requests quoted, got %+v", hard)
+		}
+	}
+
+	got, _ := quota.Get()
+	got.Status.Hard = got.Spec.Hard
+	got.Status.Used = v1.ResourceList{
+		"requests.cpu": resource.MustParse("2"),
+		"limits.cpu":   resource.MustParse("30"),
+	}
+	if _, err := client.CoreV1().ResourceQuotas("default").UpdateStatus(quota.ctx, got, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if fits, err := quota.Fits(base.Resource{CPUNum: 4}); err != nil || fits {
+		t.Errorf("expect job exceeds the cpu limits, err: %v", err)
+	}
+	if fits, err := quota.Fits(base.Resource{CPUNum: 2}); err != nil || !fits {
+		t.Errorf("expect job fits, err: %v", err)
+	}
+}
+
+func TestResourceQuotaEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	quota := NewResourceQuota(nil).LinkClient(client).Metadata("tenant", "default").
+		Resource(base.Resource{CPUNum: 32, MemorySize: 128})
+	if err := quota.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !quota.Equal(nil) {
+		t.Error("expect equal to the created quota")
+	}
+	other := NewResourceQuota(nil).LinkClient(client).Metadata("tenant", "default").
+		Resource(base.Resource{CPUNum: 64, MemorySize: 128})
+	if other.Equal(nil) {
+		t.Error("expect not equal with other hard limits")
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/21 10:05:44
+ Desc     : role
+*/
+
+package kube
+
+import (
+	"context"
+
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Role struct {
+	*LinkInfo
+	*v1.Role
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewRole(ctx context.Context) *Role {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &Role{
+		Role: &v1.Role{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Role",
+				APIVersion: "rbac.authorization.k8s.io/v1",
+			},
+			Rules: []v1.PolicyRule{},
+		},
+		ctx:      ctx,
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+	}
+}
+
+func (r *Role) Metadata(name, namespace string) *Role {
+	r.Name = name
+	r.Namespace = namespace
+	return r
+}
+
+func (r *Role) Labels(labels map[string]string) *Role {
+	if r.Role.Labels == nil {
+		r.Role.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		r.Role.Labels[k] = v
+	}
+	return r
+}
+
+func (r *Role) Annotations(annotations map[string]string) *Role {
+	if r.Role.Annotations == nil {
+		r.Role.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		r.Role.Annotations[k] = v
+	}
+	return r
+}
+
+func (r *Role) Link(region, config string, opts ...ClientOption) *Role {
+	r.Region = region
+	r.Config = config
+	r.client, r.err = NewKubeClient(region, config, opts...)
+	return r
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (r *Role) LinkClient(client kubernetes.Interface) *Role {
+	r.client, r.err = NewKubeClientFor(client), nil
+	return r
+}
+
+func (r *Role) Rule(verbs []string, apiGroups []string, resources []string) *Role {
+	if len(verbs) == 0 || len(apiGroups) == 0 || len(resources) == 0 {
+		return r
+	}
+	r.Rules = append(r.Rules, v1.PolicyRule{
+		Verbs:     verbs,
+		APIGroups: apiGroups,
+		Resources: resources,
+	})
+	return r
+}
+
+func (r *Role) Create() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.RbacV1().Roles(r.Namespace).Create(r.ctx, r.Role, metav1.CreateOptions{})
+	return err
+}
+
+func (r *Role) Update() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.RbacV1().Roles(r.Namespace).Update(r.ctx, r.Role, metav1.UpdateOptions{})
+	return err
+}
+
+func (r *Role) Delete() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.client.RbacV1().Roles(r.Namespace).Delete(r.ctx, r.Name, metav1.DeleteOptions{})
+}
+
+func (r *Role) Get() (*v1.Role, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.client.RbacV1().Roles(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+}
+
+func (r *Role) Empty() bool {
+	if r.err != nil {
+		return false
+	}
+	_, err := r.client.RbacV1().Roles(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (r *Role) CreateOrUpdate() error {
+	if r.err != nil {
+		return r.err
+	}
+	_, err := r.client.RbacV1().Roles(r.Namespace).Get(r.ctx, r.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.Create()
+		}
+		return err
+	}
+	return r.Update()
+}
+
+func (r *Role) List() (*v1.RoleList, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.client.RbacV1().Roles(r.Namespace).List(r.ctx, metav1.ListOptions{})
+}
+
+func (r *Role) Equal(keys []string) bool {
+	if r.err != nil {
+		return false
+	}
+	role, err := r.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"^Rules.*$"}
+	}
+	keys = append(keys, "^Metadata.Labels.*$")
+	keys = append(keys, "^Metadata.Annotations.*$")
+
+	return ResourceEqual(r.Role, role, keys)
+}
@@ -0,0 +1,179 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/21 11:32:18
+ Desc     : role binding
+*/
+
+package kube
+
+import (
+	"context"
+
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type RoleBinding struct {
+	*LinkInfo
+	*v1.RoleBinding
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewRoleBinding(ctx context.Context) *RoleBinding {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &RoleBinding{
+		RoleBinding: &v1.RoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "RoleBinding",
+				APIVersion: "rbac.authorization.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Subjects:   []v1.Subject{},
+			RoleRef:    v1.RoleRef{},
+		},
+		ctx:      ctx,
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+	}
+}
+
+func (rb *RoleBinding) Metadata(name, namespace string) *RoleBinding {
+	rb.Name = name
+	rb.Namespace = namespace
+	return rb
+}
+
+func (rb *RoleBinding) Link(region, config string, opts ...ClientOption) *RoleBinding {
+	rb.Region = region
+	rb.Config = config
+	rb.client, rb.err = NewKubeClient(region, config, opts...)
+	return rb
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (rb *RoleBinding) LinkClient(client kubernetes.Interface) *RoleBinding {
+	rb.client, rb.err = NewKubeClientFor(client), nil
+	return rb
+}
+
+func (rb *RoleBinding) Labels(labels map[string]string) *RoleBinding {
+	if rb.RoleBinding.Labels == nil {
+		rb.RoleBinding.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		rb.RoleBinding.Labels[k] = v
+	}
+	return rb
+}
+
+func (rb *RoleBinding) Annotations(annotations map[string]string) *RoleBinding {
+	if rb.RoleBinding.Annotations == nil {
+		rb.RoleBinding.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		rb.RoleBinding.Annotations[k] = v
+	}
+	return rb
+}
+
+func (rb *RoleBinding) Subject(kind, apigroup, name, namespace string) *RoleBinding {
+	rb.Subjects = append(rb.Subjects, v1.Subject{
+		Kind:      kind,
+		APIGroup:  apigroup,
+		Name:      name,
+		Namespace: namespace,
+	})
+	return rb
+}
+
+// RoleRef refers to a Role in the namespace of the binding or a ClusterRole,
+// whose permissions are then granted within the namespace only.
+func (rb *RoleBinding) RoleRef(kind, apigroup, name string) *RoleBinding {
+	rb.RoleBinding.RoleRef.Kind = kind
+	rb.RoleBinding.RoleRef.APIGroup = apigroup
+	rb.RoleBinding.RoleRef.Name = name
+	return rb
+}
+
+func (rb *RoleBinding) Create() error {
+	if rb.err != nil {
+		return rb.err
+	}
+	_, err := rb.client.RbacV1().RoleBindings(rb.Namespace).Create(rb.ctx, rb.RoleBinding, metav1.CreateOptions{})
+	return err
+}
+
+func (rb *RoleBinding) Get() (*v1.RoleBinding, error) {
+	if rb.err != nil {
+		return nil, rb.err
+	}
+	return rb.client.RbacV1().RoleBindings(rb.Namespace).Get(rb.ctx, rb.Name, metav1.GetOptions{})
+}
+
+func (rb *RoleBinding) Delete() error {
+	if rb.err != nil {
+		return rb.err
+	}
+	return rb.client.RbacV1().RoleBindings(rb.Namespace).Delete(rb.ctx, rb.Name, metav1.DeleteOptions{})
+}
+
+func (rb *RoleBinding) Update() error {
+	if rb.err != nil {
+		return rb.err
+	}
+	_, err := rb.client.RbacV1().RoleBindings(rb.Namespace).Update(rb.ctx, rb.RoleBinding, metav1.UpdateOptions{})
+	return err
+}
+
+func (rb *RoleBinding) Empty() bool {
+	if rb.err != nil {
+		return false
+	}
+	_, err := rb.client.RbacV1().RoleBindings(rb.Namespace).Get(rb.ctx, rb.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (rb *RoleBinding) List() (*v1.RoleBindingList, error) {
+	if rb.err != nil {
+		return nil, rb.err
+	}
+	return rb.client.RbacV1().RoleBindings(rb.Namespace).List(rb.ctx, metav1.ListOptions{})
+}
+
+func (rb *RoleBinding) CreateOrUpdate() error {
+	if rb.err != nil {
+		return rb.err
+	}
+	_, err := rb.client.RbacV1().RoleBindings(rb.Namespace).Get(rb.ctx, rb.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return rb.Create()
+		}
+		return err
+	}
+	return rb.Update()
+}
+
+func (rb *RoleBinding) Equal(keys []string) bool {
+	if rb.err != nil {
+		return false
+	}
+	roleBinding, err := rb.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Subjects", "RoleRef"}
+	}
+	return ResourceEqual(rb.RoleBinding, roleBinding, keys)
+}
@@ -0,0 +1,253 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/02 10:14:52
+ Desc     : rollout history, undo, pause and resume of deployments
+*/
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	RevisionAnnotation    = "deployment.kubernetes.io/revision"
+	ChangeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// Revision is a revision of a deployment kept by one of its replicasets.
+type Revision struct {
+	Revision        int64
+	ChangeCause     string
+	PodTemplateHash string
+	ReplicaSet      string
+	Replicas        int32
+	Template        v1.PodTemplateSpec
+	CreatedAt       metav1.Time
+}
+
+// RolloutStatus summarizes the progress of a rollout like `kubectl rollout
+// status`.
+type RolloutStatus struct {
+	Revision  int64
+	Replicas  int32
+	Updated   int32
+	Ready     int32
+	Available int32
+	Paused    bool
+	// Complete is true when all replicas run the latest template
+	Complete bool
+	Message  string
+}
+
+// History returns the revisions of the deployment in order, like `kubectl
+// rollout history`.
+func (d *Deployment) History() ([]Revision, error) {
+	deploy, err := d.Get()
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := d.replicaSets(d.ctx, deploy)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(replicaSets))
+	for _, rs := range replicaSets {
+		revision, err := strconv.ParseInt(rs.Annotations[RevisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		replicas := int32(0)
+		if rs.Spec.Replicas != nil {
+			replicas = *rs.Spec.Replicas
+		}
+		revisions = append(revisions, Revision{
+			Revision:        revision,
+			ChangeCause:     rs.Annotations[ChangeCauseAnnotation],
+			PodTemplateHash: rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey],
+			ReplicaSet:      rs.Name,
+			Replicas:        replicas,
+			Template:        rs.Spec.Template,
+			CreatedAt:       rs.CreationTimestamp,
+		})
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// Undo rolls the template back to the revision, or to the previous revision
+// when toRevision is 0, like `kubectl rollout undo`.
+func (d *Deployment) Undo(toRevision int64) error {
+	deploy, err := d.Get()
+	if err != nil {
+		return err
+	}
+	if deploy.Spec.Paused {
+		return fmt.Errorf("deployment %s is paused, resume it before undo", d.Name)
+	}
+	revisions, err := d.History()
+	if err != nil {
+		return err
+	}
+	var target *Revision
+	if toRevision == 0 {
+		if len(revisions) < 2 {
+			return fmt.Errorf("deployment %s has no previous revision", d.Name)
+		}
+		target = &revisions[len(revisions)-2]
+	} else {
+		for i := range revisions {
+			if revisions[i].Revision == toRevision {
+				target = &revisions[i]
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("deployment %s has no revision %d", d.Name, toRevision)
+		}
+	}
+	template := target.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = d.client.AppsV1().Deployments(d.Namespace).Patch(d.ctx, d.Name,
+		types.JSONPatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Pause stops rolling out the changes of the template until Resume.
+func (d *Deployment) Pause() error {
+	return d.pause(true)
+}
+
+// Resume rolls out the changes made while the deployment was paused.
+func (d *Deployment) Resume() error {
+	return d.pause(false)
+}
+
+func (d *Deployment) pause(paused bool) error {
+	if d.err != nil {
+		return d.err
+	}
+	data := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	_, err := d.client.AppsV1().Deployments(d.Namespace).Patch(d.ctx, d.Name,
+		types.MergePatchType, []byte(data), metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	d.Deployment.Spec.Paused = paused
+	return nil
+}
+
+// Status returns the progress of the rollout, the messages follow `kubectl
+// rollout status`.
+func (d *Deployment) Status() (*RolloutStatus, error) {
+	deploy, err := d.Get()
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	revision, _ := strconv.ParseInt(deploy.Annotations[RevisionAnnotation], 10, 64)
+	status := &RolloutStatus{
+		Revision:  revision,
+		Replicas:  replicas,
+		Updated:   deploy.Status.UpdatedReplicas,
+		Ready:     deploy.Status.ReadyReplicas,
+		Available: deploy.Status.AvailableReplicas,
+		Paused:    deploy.Spec.Paused,
+	}
+	if deploy.Generation > deploy.Status.ObservedGeneration {
+		status.Message = "waiting for deployment spec update to be observed"
+		return status, nil
+	}
+	for _, cond := range deploy.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == ReasonProgressDeadlineExceeded {
+			status.Message = fmt.Sprintf("deployment %q exceeded its progress deadline", d.Name)
+			return status, nil
+		}
+	}
+	switch {
+	case status.Updated < replicas:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated",
+			d.Name, status.Updated, replicas)
+	case deploy.Status.Replicas > status.Updated:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d old replicas are pending termination",
+			d.Name, deploy.Status.Replicas-status.Updated)
+	case status.Available < status.Updated:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d of %d updated replicas are available",
+			d.Name, status.Available, status.Updated)
+	default:
+		status.Complete = true
+		status.Message = fmt.Sprintf("deployment %q successfully rolled out", d.Name)
+	}
+	return status, nil
+}
+
+// replicaSets returns the replicasets controlled by the deployment.
+func (d *Deployment) replicaSets(ctx context.Context, deploy *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	list, err := d.client.AppsV1().ReplicaSets(d.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	replicaSets := make([]appsv1.ReplicaSet, 0, len(list.Items))
+	for _, rs := range list.Items {
+		if metav1.IsControlledBy(&rs, deploy) {
+			replicaSets = append(replicaSets, rs)
+		}
+	}
+	return replicaSets, nil
+}
+
+// newReplicaSet returns the replicaset of the current revision, the one with
+// the highest revision when the deployment has no revision yet, or nil
+// before the controller created it.
+func (d *Deployment) newReplicaSet(ctx context.Context, deploy *appsv1.Deployment) (*appsv1.ReplicaSet, error) {
+	replicaSets, err := d.replicaSets(ctx, deploy)
+	if err != nil {
+		return nil, err
+	}
+	current, hasCurrent := deploy.Annotations[RevisionAnnotation]
+	var (
+		newest   *appsv1.ReplicaSet
+		revision int64 = -1
+	)
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if hasCurrent {
+			if rs.Annotations[RevisionAnnotation] == current {
+				return rs, nil
+			}
+			continue
+		}
+		if r, err := strconv.ParseInt(rs.Annotations[RevisionAnnotation], 10, 64); err == nil && r > revision {
+			newest, revision = rs, r
+		}
+	}
+	return newest, nil
+}
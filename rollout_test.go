@@ -0,0 +1,163 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/02 15:20:37
+ Desc     :
+*/
+
+package kube
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeploymentRollout(t *testing.T) {
+	ctx := context.TODO()
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).LinkClient(client).Metadata("inference", "default").
+		Replicas(2).Selector(map[string]string{"app": "inference"})
+	deployment.Deployment.UID = "uid-inference"
+	if err := deployment.Create(); err != nil {
+		t.Fatal(err)
+	}
+	controller := true
+	for i, image := range []string{"inference:v1", "inference:v2", "inference:v3"} {
+		hash := "hash" + strconv.Itoa(i+1)
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "inference-" + hash,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "inference", appsv1.DefaultDeploymentUniqueLabelKey: hash},
+				Annotations: map[string]string{
+					RevisionAnnotation:    strconv.Itoa(3 - i),
+					ChangeCauseAnnotation: "set image " + image,
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "inference",
+					UID:        "uid-inference",
+					Controller: &controller,
+				}},
+			},
+			Spec: appsv1.ReplicaSetSpec{
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": "inference", appsv1.DefaultDeploymentUniqueLabelKey: hash},
+					},
+					Spec: v1.PodSpec{Containers: []v1.Container{{Name: "inference", Image: image}}},
+				},
+			},
+		}
+		if _, err := client.AppsV1().ReplicaSets("default").Create(ctx, rs, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// not controlled by the deployment
+	orphan := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "orphan",
+		Namespace:   "default",
+		Labels:      map[string]string{"app": "inference"},
+		Annotations: map[string]string{RevisionAnnotation: "9"},
+	}}
+	if _, err := client.AppsV1().ReplicaSets("default").Create(ctx, orphan, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := deployment.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(revisions))
+	}
+	last := revisions[2]
+	if last.Revision != 3 || last.PodTemplateHash != "hash1" || last.ChangeCause != "set image inference:v1" {
+		t.Fatalf("unexpected revision %+v", last)
+	}
+
+	// undo to the previous revision
+	if err := deployment.Undo(0); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := deployment.Get()
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "inference:v2" {
+		t.Fatalf("expected image inference:v2, got %s", image)
+	}
+	if _, ok := got.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		t.Fatal("pod-template-hash should be removed from the template")
+	}
+	if err := deployment.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = deployment.Get()
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "inference:v3" {
+		t.Fatalf("expected image inference:v3, got %s", image)
+	}
+	if err := deployment.Undo(7); err == nil {
+		t.Fatal("expected error for unknown revision")
+	}
+
+	// paused deployments can not be undone
+	if err := deployment.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = deployment.Get(); !got.Spec.Paused {
+		t.Fatal("expected deployment to be paused")
+	}
+	if err := deployment.Undo(0); err == nil {
+		t.Fatal("expected error for undo of a paused deployment")
+	}
+	if err := deployment.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = deployment.Get(); got.Spec.Paused {
+		t.Fatal("expected deployment to be resumed")
+	}
+}
+
+func TestDeploymentStatus(t *testing.T) {
+	ctx := context.TODO()
+	client := fake.NewSimpleClientset()
+	deployment := NewDeployment(nil).LinkClient(client).Metadata("inference", "default").
+		Replicas(2).Selector(map[string]string{"app": "inference"})
+	if err := deployment.Create(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		status   appsv1.DeploymentStatus
+		complete bool
+		message  string
+	}{
+		{appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1},
+			false, `waiting for deployment "inference" rollout to finish: 1 out of 2 new replicas have been updated`},
+		{appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2},
+			false, `waiting for deployment "inference" rollout to finish: 1 old replicas are pending termination`},
+		{appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+			false, `waiting for deployment "inference" rollout to finish: 1 of 2 updated replicas are available`},
+		{appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2, ReadyReplicas: 2},
+			true, `deployment "inference" successfully rolled out`},
+	}
+	for _, tt := range tests {
+		got, _ := deployment.Get()
+		got.Status = tt.status
+		if _, err := client.AppsV1().Deployments("default").UpdateStatus(ctx, got, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		status, err := deployment.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Complete != tt.complete || status.Message != tt.message {
+			t.Fatalf("unexpected status %+v", status)
+		}
+	}
+}
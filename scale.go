@@ -0,0 +1,75 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/03 11:02:15
+ Desc     : scale subresource, hibernate and wake of the workloads
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HibernatedReplicasAnnotation records the replicas of a hibernated workload.
+const HibernatedReplicasAnnotation = "kube.piaobeizu.io/hibernated-replicas"
+
+// scaleClient is the scale subresource of deployments, statefulsets and
+// replicasets.
+type scaleClient interface {
+	GetScale(ctx context.Context, name string, options metav1.GetOptions) (*autoscalingv1.Scale, error)
+	UpdateScale(ctx context.Context, name string, scale *autoscalingv1.Scale, opts metav1.UpdateOptions) (*autoscalingv1.Scale, error)
+}
+
+func scaleTo(ctx context.Context, client scaleClient, name string, replicas int32) error {
+	scale, err := client.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	scale.Spec.Replicas = replicas
+	_, err = client.UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// hibernate records the replicas with patch before scaling to zero, so they
+// are not lost if the scale fails. A workload which is already scaled to
+// zero is left as is.
+func hibernate(ctx context.Context, client scaleClient, name string, patch func(data []byte) error) error {
+	scale, err := client.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if scale.Spec.Replicas == 0 {
+		return nil
+	}
+	data := fmt.Sprintf(`{"metadata":{"annotations":{%q:"%d"}}}`, HibernatedReplicasAnnotation, scale.Spec.Replicas)
+	if err := patch([]byte(data)); err != nil {
+		return err
+	}
+	scale.Spec.Replicas = 0
+	_, err = client.UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// wake scales back to the replicas recorded in the annotations and removes
+// the record, a workload which is not hibernated is left as is.
+func wake(ctx context.Context, client scaleClient, name string, annotations map[string]string, patch func(data []byte) error) error {
+	value, ok := annotations[HibernatedReplicasAnnotation]
+	if !ok {
+		return nil
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid annotation %s of %s: %w", HibernatedReplicasAnnotation, name, err)
+	}
+	if err := scaleTo(ctx, client, name, int32(replicas)); err != nil {
+		return err
+	}
+	return patch([]byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, HibernatedReplicasAnnotation)))
+}
@@ -0,0 +1,154 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/07/03 15:12:08
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// scaleReactor serves the scale subresource from spec.replicas, the fake
+// clientset does not implement it.
+func scaleReactor(client *fake.Clientset) {
+	replicas := func(obj runtime.Object) **int32 {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			return &o.Spec.Replicas
+		case *appsv1.StatefulSet:
+			return &o.Spec.Replicas
+		case *appsv1.ReplicaSet:
+			return &o.Spec.Replicas
+		}
+		return nil
+	}
+	client.PrependReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		name := action.(k8stesting.GetAction).GetName()
+		obj, err := client.Tracker().Get(action.GetResource(), action.GetNamespace(), name)
+		if err != nil {
+			return true, nil, err
+		}
+		scale := &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: action.GetNamespace()}}
+		if r := *replicas(obj); r != nil {
+			scale.Spec.Replicas = *r
+		}
+		return true, scale, nil
+	})
+	client.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		obj, err := client.Tracker().Get(action.GetResource(), action.GetNamespace(), scale.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		r := scale.Spec.Replicas
+		*replicas(obj) = &r
+		return true, scale, client.Tracker().Update(action.GetResource(), obj, action.GetNamespace())
+	})
+}
+
+func TestHibernateAndWake(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	scaleReactor(client)
+	type scalable interface {
+		Create() error
+		GetReplicas() (int32, error)
+		Scale(replicas int32) error
+		Hibernate() error
+		Wake() error
+	}
+	deployment := NewDeployment(nil).LinkClient(client).Metadata("notebook", "default").Replicas(1)
+	statefulSet := NewStatefulSet(nil).LinkClient(client).Metadata("notebook", "default").Replicas(1)
+	replicaSet := NewReplicaSet(nil).LinkClient(client).Metadata("notebook", "default").Replicas(1)
+	cases := []struct {
+		name        string
+		workload    scalable
+		annotations func() (map[string]string, error)
+	}{
+		{"Deployment", deployment, func() (map[string]string, error) {
+			got, err := deployment.Get()
+			if err != nil {
+				return nil, err
+			}
+			return got.Annotations, nil
+		}},
+		{"StatefulSet", statefulSet, func() (map[string]string, error) {
+			got, err := statefulSet.Get()
+			if err != nil {
+				return nil, err
+			}
+			return got.Annotations, nil
+		}},
+		{"ReplicaSet", replicaSet, func() (map[string]string, error) {
+			got, err := replicaSet.Get()
+			if err != nil {
+				return nil, err
+			}
+			return got.Annotations, nil
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := c.workload
+			if err := w.Create(); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Scale(3); err != nil {
+				t.Fatal(err)
+			}
+			if replicas, _ := w.GetReplicas(); replicas != 3 {
+				t.Fatalf("expected 3 replicas, got %d", replicas)
+			}
+			if err := w.Hibernate(); err != nil {
+				t.Fatal(err)
+			}
+			if replicas, _ := w.GetReplicas(); replicas != 0 {
+				t.Fatalf("expected 0 replicas, got %d", replicas)
+			}
+			annotations, err := c.annotations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if annotations[HibernatedReplicasAnnotation] != "3" {
+				t.Fatalf("expected 3 replicas recorded, got %q", annotations[HibernatedReplicasAnnotation])
+			}
+			// hibernating again keeps the record
+			if err := w.Hibernate(); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Wake(); err != nil {
+				t.Fatal(err)
+			}
+			if replicas, _ := w.GetReplicas(); replicas != 3 {
+				t.Fatalf("expected 3 replicas after wake, got %d", replicas)
+			}
+			annotations, _ = c.annotations()
+			if _, ok := annotations[HibernatedReplicasAnnotation]; ok {
+				t.Fatal("expected the record to be removed after wake")
+			}
+			// waking a running workload does nothing
+			if err := w.Wake(); err != nil {
+				t.Fatal(err)
+			}
+			if replicas, _ := w.GetReplicas(); replicas != 3 {
+				t.Fatalf("expected 3 replicas, got %d", replicas)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type Secret struct {
@@ -52,10 +53,16 @@ func (s *Secret) Type(t v1.SecretType) *Secret {
 	s.Secret.Type = t
 	return s
 }
-func (s *Secret) Link(region, config string) *Secret {
+func (s *Secret) Link(region, config string, opts ...ClientOption) *Secret {
 	s.Region = region
 	s.Config = config
-	s.client = NewKubeClient(region, config)
+	s.client, s.err = NewKubeClient(region, config, opts...)
+	return s
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (s *Secret) LinkClient(client kubernetes.Interface) *Secret {
+	s.client, s.err = NewKubeClientFor(client), nil
 	return s
 }
 
@@ -105,28 +112,43 @@ func (s *Secret) Annotations(annotations map[string]string) *Secret {
 }
 
 func (s *Secret) Create() error {
+	if s.err != nil {
+		return s.err
+	}
 	Secrets := s.client.CoreV1().Secrets(s.Namespace)
 	_, err := Secrets.Create(s.ctx, s.Secret, metav1.CreateOptions{})
 	return err
 }
 
 func (s *Secret) Delete() error {
+	if s.err != nil {
+		return s.err
+	}
 	Secrets := s.client.CoreV1().Secrets(s.Namespace)
 	return Secrets.Delete(s.ctx, s.Name, metav1.DeleteOptions{})
 }
 
 func (s *Secret) Update() error {
+	if s.err != nil {
+		return s.err
+	}
 	Secrets := s.client.CoreV1().Secrets(s.Namespace)
 	_, err := Secrets.Update(s.ctx, s.Secret, metav1.UpdateOptions{})
 	return err
 }
 
 func (s *Secret) Get() (*v1.Secret, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
 	return s.client.CoreV1().Secrets(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 
 }
 
 func (s *Secret) CreateOrUpdate() error {
+	if s.err != nil {
+		return s.err
+	}
 	_, err := s.client.CoreV1().Secrets(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -138,11 +160,17 @@ func (s *Secret) CreateOrUpdate() error {
 }
 
 func (s *Secret) Empty() bool {
+	if s.err != nil {
+		return false
+	}
 	_, err := s.client.CoreV1().Secrets(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (s *Secret) StringDataEqual() bool {
+	if s.err != nil {
+		return false
+	}
 	secret, err := s.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -155,6 +183,9 @@ func (s *Secret) StringDataEqual() bool {
 }
 
 func (s *Secret) Equal(keys []string) bool {
+	if s.err != nil {
+		return false
+	}
 	secret, err := s.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
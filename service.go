@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 )
 
 type Service struct {
@@ -43,10 +44,16 @@ func NewService(ctx context.Context) *Service {
 	}
 }
 
-func (s *Service) Link(region, config string) *Service {
+func (s *Service) Link(region, config string, opts ...ClientOption) *Service {
 	s.Region = region
 	s.Config = config
-	s.client = NewKubeClient(region, config)
+	s.client, s.err = NewKubeClient(region, config, opts...)
+	return s
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (s *Service) LinkClient(client kubernetes.Interface) *Service {
+	s.client, s.err = NewKubeClientFor(client), nil
 	return s
 }
 
@@ -102,32 +109,50 @@ func (s *Service) Type(serviceType v1.ServiceType) *Service {
 }
 
 func (s *Service) Create() error {
+	if s.err != nil {
+		return s.err
+	}
 	_, err := s.client.CoreV1().Services(s.Namespace).
 		Create(s.ctx, s.Service, metav1.CreateOptions{})
 	return err
 }
 
 func (s *Service) Update() error {
+	if s.err != nil {
+		return s.err
+	}
 	_, err := s.client.CoreV1().Services(s.Namespace).
 		Update(s.ctx, s.Service, metav1.UpdateOptions{})
 	return err
 }
 
 func (s *Service) Delete() error {
+	if s.err != nil {
+		return s.err
+	}
 	return s.client.CoreV1().Services(s.Namespace).
 		Delete(s.ctx, s.Name, metav1.DeleteOptions{})
 }
 
 func (s *Service) Get() (*v1.Service, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
 	return s.client.CoreV1().Services(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 }
 
 func (s *Service) Empty() bool {
+	if s.err != nil {
+		return false
+	}
 	_, err := s.client.CoreV1().Services(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 	return errors.IsNotFound(err)
 }
 
 func (s *Service) CreateOrUpdate() error {
+	if s.err != nil {
+		return s.err
+	}
 	_, err := s.client.CoreV1().Services(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -139,6 +164,9 @@ func (s *Service) CreateOrUpdate() error {
 }
 
 func (s *Service) Equal(keys []string) bool {
+	if s.err != nil {
+		return false
+	}
 	service, err := s.Get()
 	if !errors.IsNotFound(err) {
 		return false
@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type ServiceAccount struct {
@@ -64,10 +65,16 @@ func (sa *ServiceAccount) Annotations(annotations map[string]string) *ServiceAcc
 	return sa
 }
 
-func (sa *ServiceAccount) Link(region, config string) *ServiceAccount {
+func (sa *ServiceAccount) Link(region, config string, opts ...ClientOption) *ServiceAccount {
 	sa.Region = region
 	sa.Config = config
-	sa.client = NewKubeClient(region, config)
+	sa.client, sa.err = NewKubeClient(region, config, opts...)
+	return sa
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (sa *ServiceAccount) LinkClient(client kubernetes.Interface) *ServiceAccount {
+	sa.client, sa.err = NewKubeClientFor(client), nil
 	return sa
 }
 
@@ -88,29 +95,54 @@ func (sa *ServiceAccount) Secret(kind, secretName, namespace string) *ServiceAcc
 	return sa
 }
 
+func (sa *ServiceAccount) ImagePullSecrets(secrets []string) *ServiceAccount {
+	for _, secret := range secrets {
+		sa.ServiceAccount.ImagePullSecrets = append(sa.ServiceAccount.ImagePullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+	return sa
+}
+
 func (sa *ServiceAccount) Create() error {
+	if sa.err != nil {
+		return sa.err
+	}
 	_, err := sa.client.CoreV1().ServiceAccounts(sa.Namespace).Create(sa.ctx, sa.ServiceAccount, metav1.CreateOptions{})
 	return err
 }
 
 func (sa *ServiceAccount) Get() (*v1.ServiceAccount, error) {
+	if sa.err != nil {
+		return nil, sa.err
+	}
 	return sa.client.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.ctx, sa.Name, metav1.GetOptions{})
 }
 
 func (sa *ServiceAccount) Update() error {
+	if sa.err != nil {
+		return sa.err
+	}
 	_, err := sa.client.CoreV1().ServiceAccounts(sa.Namespace).Update(sa.ctx, sa.ServiceAccount, metav1.UpdateOptions{})
 	return err
 }
 
 func (sa *ServiceAccount) Delete() error {
+	if sa.err != nil {
+		return sa.err
+	}
 	return sa.client.CoreV1().ServiceAccounts(sa.Namespace).Delete(sa.ctx, sa.Name, metav1.DeleteOptions{})
 }
 
 func (sa *ServiceAccount) List() (*v1.ServiceAccountList, error) {
+	if sa.err != nil {
+		return nil, sa.err
+	}
 	return sa.client.CoreV1().ServiceAccounts(sa.Namespace).List(sa.ctx, metav1.ListOptions{})
 }
 
 func (sa *ServiceAccount) Empty() bool {
+	if sa.err != nil {
+		return false
+	}
 	_, err := sa.client.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.ctx, sa.Name, metav1.GetOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -119,6 +151,9 @@ func (sa *ServiceAccount) Empty() bool {
 }
 
 func (sa *ServiceAccount) CreateOrUpdate() error {
+	if sa.err != nil {
+		return sa.err
+	}
 	_, err := sa.client.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.ctx, sa.Name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -130,6 +165,9 @@ func (sa *ServiceAccount) CreateOrUpdate() error {
 }
 
 func (sa *ServiceAccount) Equal(keys []string) bool {
+	if sa.err != nil {
+		return false
+	}
 	serviceAccount, err := sa.Get()
 	if err != nil && !errors.IsNotFound(err) {
 		panic(err)
@@ -139,3 +177,27 @@ func (sa *ServiceAccount) Equal(keys []string) bool {
 	}
 	return ResourceEqual(sa.ServiceAccount, serviceAccount, keys)
 }
+
+// BindRole grants the role to the service account by the role binding
+// <serviceaccount>-<role> in the namespace of the service account.
+func (sa *ServiceAccount) BindRole(role *Role) error {
+	return sa.bind("Role", role.Name)
+}
+
+// BindClusterRole grants the cluster role to the service account within the
+// namespace of the service account only.
+func (sa *ServiceAccount) BindClusterRole(role *ClusterRole) error {
+	return sa.bind("ClusterRole", role.Name)
+}
+
+func (sa *ServiceAccount) bind(kind, roleName string) error {
+	if sa.err != nil {
+		return sa.err
+	}
+	return NewRoleBinding(sa.ctx).LinkClient(sa.client.Interface).
+		Metadata(sa.Name+"-"+roleName, sa.Namespace).
+		Labels(sa.ServiceAccount.Labels).
+		Subject("ServiceAccount", "", sa.Name, sa.Namespace).
+		RoleRef(kind, "rbac.authorization.k8s.io", roleName).
+		CreateOrUpdate()
+}
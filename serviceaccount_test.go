@@ -0,0 +1,45 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/21 15:47:09
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServiceAccountBind(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sa := NewServiceAccount(nil).LinkClient(client).Metadata("trainer", "tenant-a")
+	if err := sa.Create(); err != nil {
+		t.Fatal(err)
+	}
+	role := NewRole(nil).Metadata("pod-reader", "tenant-a").
+		Rule([]string{"get", "list", "watch"}, []string{""}, []string{"pods", "pods/log"})
+	if err := sa.BindRole(role); err != nil {
+		t.Fatal(err)
+	}
+	if err := sa.BindClusterRole(NewClusterRole(nil).Metadata("view")); err != nil {
+		t.Fatal(err)
+	}
+	// binding again is idempotent
+	if err := sa.BindClusterRole(NewClusterRole(nil).Metadata("view")); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, kind := range map[string]string{"trainer-pod-reader": "Role", "trainer-view": "ClusterRole"} {
+		rb, err := NewRoleBinding(nil).LinkClient(client).Metadata(name, "tenant-a").Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rb.RoleRef.Kind != kind || len(rb.Subjects) != 1 || rb.Subjects[0].Name != "trainer" || rb.Subjects[0].Namespace != "tenant-a" {
+			t.Errorf("unexpected role binding %s: %v %v", name, rb.RoleRef, rb.Subjects)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/07 14:26:03
+ Desc     : statefulset
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type StatefulSet struct {
+	*LinkInfo
+	*appsv1.StatefulSet
+	ctx    context.Context
+	client *KubeClient
+}
+
+func NewStatefulSet(ctx context.Context) *StatefulSet {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &StatefulSet{
+		StatefulSet: &appsv1.StatefulSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "StatefulSet",
+				APIVersion: "apps/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       appsv1.StatefulSetSpec{},
+		},
+		LinkInfo: &LinkInfo{},
+		client:   nil,
+		ctx:      ctx,
+	}
+}
+
+func (s *StatefulSet) Link(region, config string, opts ...ClientOption) *StatefulSet {
+	s.Region = region
+	s.Config = config
+	s.client, s.err = NewKubeClient(region, config, opts...)
+	return s
+}
+
+// LinkClient uses the given clientset instead of building one from kubeconfig.
+func (s *StatefulSet) LinkClient(client kubernetes.Interface) *StatefulSet {
+	s.client, s.err = NewKubeClientFor(client), nil
+	return s
+}
+
+func (s *StatefulSet) Metadata(name, namespace string) *StatefulSet {
+	s.Name, s.Namespace = name, namespace
+	return s
+}
+
+func (s *StatefulSet) Labels(labels map[string]string) *StatefulSet {
+	if s.StatefulSet.Labels == nil {
+		s.StatefulSet.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		s.StatefulSet.Labels[k] = v
+	}
+	return s
+}
+
+func (s *StatefulSet) Annotations(annotations map[string]string) *StatefulSet {
+	if s.StatefulSet.Annotations == nil {
+		s.StatefulSet.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		s.StatefulSet.Annotations[k] = v
+	}
+	return s
+}
+
+func (s *StatefulSet) Replicas(replicas int32) *StatefulSet {
+	s.StatefulSet.Spec.Replicas = &replicas
+	return s
+}
+
+func (s *StatefulSet) Selector(selector map[string]string) *StatefulSet {
+	s.StatefulSet.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: selector,
+	}
+	return s
+}
+
+func (s *StatefulSet) Template(pod *PodTemplate) *StatefulSet {
+	if pod == nil {
+		return s
+	}
+	s.StatefulSet.Spec.Template = pod.Template
+	return s
+}
+
+// ServiceName is the headless service which gives the pods their network
+// identity, it must exist before the statefulset.
+func (s *StatefulSet) ServiceName(serviceName string) *StatefulSet {
+	s.StatefulSet.Spec.ServiceName = serviceName
+	return s
+}
+
+func (s *StatefulSet) VolumeClaimTemplate(claim v1.PersistentVolumeClaim) *StatefulSet {
+	if s.StatefulSet.Spec.VolumeClaimTemplates == nil {
+		s.StatefulSet.Spec.VolumeClaimTemplates = make([]v1.PersistentVolumeClaim, 0)
+	}
+	s.StatefulSet.Spec.VolumeClaimTemplates = append(s.StatefulSet.Spec.VolumeClaimTemplates, claim)
+	return s
+}
+
+func (s *StatefulSet) PodManagementPolicy(policy appsv1.PodManagementPolicyType) *StatefulSet {
+	s.StatefulSet.Spec.PodManagementPolicy = policy
+	return s
+}
+
+// UpdateStrategy sets the update strategy, pods with an ordinal less than
+// partition are not updated by a RollingUpdate.
+func (s *StatefulSet) UpdateStrategy(strategy appsv1.StatefulSetUpdateStrategyType, partition int32) *StatefulSet {
+	s.StatefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: strategy,
+	}
+	if strategy == appsv1.RollingUpdateStatefulSetStrategyType && partition > 0 {
+		s.StatefulSet.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		}
+	}
+	return s
+}
+
+func (s *StatefulSet) PersistentVolumeClaimRetentionPolicy(whenDeleted, whenScaled appsv1.PersistentVolumeClaimRetentionPolicyType) *StatefulSet {
+	s.StatefulSet.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: whenDeleted,
+		WhenScaled:  whenScaled,
+	}
+	return s
+}
+
+func (s *StatefulSet) Create() error {
+	if s.err != nil {
+		return s.err
+	}
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).
+		Create(s.ctx, s.StatefulSet, metav1.CreateOptions{})
+	return err
+}
+
+func (s *StatefulSet) Delete() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.client.AppsV1().StatefulSets(s.Namespace).
+		Delete(s.ctx, s.Name, metav1.DeleteOptions{})
+}
+
+func (s *StatefulSet) Update() error {
+	if s.err != nil {
+		return s.err
+	}
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).Update(s.ctx, s.StatefulSet, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *StatefulSet) Get() (*appsv1.StatefulSet, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.client.AppsV1().StatefulSets(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
+}
+
+func (s *StatefulSet) Empty() bool {
+	if s.err != nil {
+		return false
+	}
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).
+		Get(s.ctx, s.Name, metav1.GetOptions{})
+	return errors.IsNotFound(err)
+}
+
+func (s *StatefulSet) CreateOrUpdate() error {
+	if s.err != nil {
+		return s.err
+	}
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).Get(s.ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return s.Create()
+		}
+		return err
+	}
+	return s.Update()
+}
+
+// implement the rollout method
+func (s *StatefulSet) Rollout() error {
+	if s.err != nil {
+		return s.err
+	}
+	data := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, time.Now().Format(time.RFC3339))
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).Patch(s.ctx, s.StatefulSet.Name,
+		types.StrategicMergePatchType, []byte(data), metav1.PatchOptions{FieldManager: "kubectl-rollout"})
+	return err
+}
+
+// WithPDB creates or updates the pod disruption budget with the selector of
+// the statefulset, so that an eviction never takes down all the replicas at
+// once.
+func (s *StatefulSet) WithPDB(pdb *PodDisruptionBudget) error {
+	if s.err != nil {
+		return s.err
+	}
+	if pdb == nil {
+		return nil
+	}
+	if s.StatefulSet.Spec.Selector == nil {
+		return fmt.Errorf("statefulset %s has no selector", s.Name)
+	}
+	return applyWorkloadPDB(s.client, pdb, s.Name, s.Namespace, s.StatefulSet.Spec.Selector)
+}
+
+func (s *StatefulSet) GetReplicas() (int32, error) {
+	sts, err := s.Get()
+	if err != nil {
+		return 0, err
+	}
+	return *sts.Spec.Replicas, nil
+}
+
+// Scale sets the replicas through the scale subresource, the rest of the
+// statefulset is left untouched.
+func (s *StatefulSet) Scale(replicas int32) error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := scaleTo(s.ctx, s.client.AppsV1().StatefulSets(s.Namespace), s.Name, replicas); err != nil {
+		return err
+	}
+	s.StatefulSet.Spec.Replicas = &replicas
+	return nil
+}
+
+// Hibernate scales the statefulset to zero, the replicas are recorded in the
+// HibernatedReplicasAnnotation for Wake.
+func (s *StatefulSet) Hibernate() error {
+	if s.err != nil {
+		return s.err
+	}
+	return hibernate(s.ctx, s.client.AppsV1().StatefulSets(s.Namespace), s.Name, s.patch)
+}
+
+// Wake restores the replicas recorded by Hibernate.
+func (s *StatefulSet) Wake() error {
+	sts, err := s.Get()
+	if err != nil {
+		return err
+	}
+	return wake(s.ctx, s.client.AppsV1().StatefulSets(s.Namespace), s.Name, sts.Annotations, s.patch)
+}
+
+func (s *StatefulSet) patch(data []byte) error {
+	_, err := s.client.AppsV1().StatefulSets(s.Namespace).Patch(s.ctx, s.Name,
+		types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+func (s *StatefulSet) Equal(keys []string) bool {
+	if s.err != nil {
+		return false
+	}
+	statefulSet, err := s.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"Spec"}
+	}
+	return ResourceEqual(s.StatefulSet, statefulSet, keys)
+}
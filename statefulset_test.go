@@ -0,0 +1,32 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/07 15:12:40
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStatefulSetEqual(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sts := NewStatefulSet(nil).LinkClient(client).Metadata("etcd", "default").
+		Replicas(1).Selector(map[string]string{"app": "etcd"})
+	if err := sts.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if !sts.Equal(nil) {
+		t.Error("expect equal to the created statefulset")
+	}
+	other := NewStatefulSet(nil).LinkClient(client).Metadata("etcd", "default").
+		Replicas(5).Selector(map[string]string{"app": "etcd"}).ServiceName("etcd")
+	if other.Equal(nil) {
+		t.Error("expect not equal with other replicas")
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/28 10:52:07
+ Desc     : unstructured object of any kind, e.g. CRDs
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/dynamic"
+)
+
+type Unstructured struct {
+	*LinkInfo
+	*unstructured.Unstructured
+	ctx     context.Context
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+	// setErr keeps the failure of Set apart from the link error, so that
+	// linking after Set does not drop it
+	setErr error
+}
+
+func NewUnstructured(ctx context.Context) *Unstructured {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	return &Unstructured{
+		Unstructured: &unstructured.Unstructured{
+			Object: map[string]interface{}{},
+		},
+		LinkInfo: &LinkInfo{},
+		ctx:      ctx,
+	}
+}
+
+// Err returns the error of the last Link, or else the first error of Set.
+func (u *Unstructured) Err() error {
+	if u.err != nil {
+		return u.err
+	}
+	return u.setErr
+}
+
+func (u *Unstructured) Link(region, config string, opts ...ClientOption) *Unstructured {
+	u.Region = region
+	u.Config = config
+	client, err := NewKubeClient(region, config, opts...)
+	if err != nil {
+		u.err = err
+		return u
+	}
+	if client.Dynamic() == nil || client.RESTMapper() == nil {
+		u.err = fmt.Errorf("client of region %s has no dynamic client", region)
+		return u
+	}
+	u.dynamic, u.mapper, u.err = client.Dynamic(), client.RESTMapper(), nil
+	return u
+}
+
+// LinkClient uses the given dynamic client and mapper instead of building
+// them from kubeconfig.
+func (u *Unstructured) LinkClient(client dynamic.Interface, mapper meta.RESTMapper) *Unstructured {
+	u.dynamic, u.mapper, u.err = client, mapper, nil
+	return u
+}
+
+// TypeMeta sets the kind of the object, e.g. batch.volcano.sh/v1alpha1 Job.
+func (u *Unstructured) TypeMeta(apiVersion, kind string) *Unstructured {
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	return u
+}
+
+func (u *Unstructured) Metadata(name, namespace string) *Unstructured {
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func (u *Unstructured) Labels(labels map[string]string) *Unstructured {
+	merged := u.GetLabels()
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	u.SetLabels(merged)
+	return u
+}
+
+func (u *Unstructured) Annotations(annotations map[string]string) *Unstructured {
+	merged := u.GetAnnotations()
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	u.SetAnnotations(merged)
+	return u
+}
+
+// Set sets the value at the field path, e.g. Set(2, "spec", "minAvailable"),
+// the value is converted through json so typed values like
+// []v1.Toleration can be set as well.
+func (u *Unstructured) Set(value interface{}, fields ...string) *Unstructured {
+	if err := u.set(value, fields...); err != nil && u.setErr == nil {
+		u.setErr = fmt.Errorf("set field %s error: %w", strings.Join(fields, "."), err)
+	}
+	return u
+}
+
+func (u *Unstructured) set(value interface{}, fields ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var converted interface{}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(u.Object, converted, fields...)
+}
+
+func (u *Unstructured) Create() error {
+	resource, err := u.resource()
+	if err != nil {
+		return err
+	}
+	_, err = resource.Create(u.ctx, u.Unstructured, metav1.CreateOptions{})
+	return err
+}
+
+func (u *Unstructured) Delete() error {
+	resource, err := u.resource()
+	if err != nil {
+		return err
+	}
+	return resource.Delete(u.ctx, u.GetName(), metav1.DeleteOptions{})
+}
+
+// Update replaces the object in the cluster, the resource version is taken
+// from the cluster when it is not set as custom resources reject an
+// unconditional update.
+func (u *Unstructured) Update() error {
+	resource, err := u.resource()
+	if err != nil {
+		return err
+	}
+	if u.GetResourceVersion() == "" {
+		current, err := resource.Get(u.ctx, u.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		u.SetResourceVersion(current.GetResourceVersion())
+		defer u.SetResourceVersion("")
+	}
+	_, err = resource.Update(u.ctx, u.Unstructured, metav1.UpdateOptions{})
+	return err
+}
+
+func (u *Unstructured) Get() (*unstructured.Unstructured, error) {
+	resource, err := u.resource()
+	if err != nil {
+		return nil, err
+	}
+	return resource.Get(u.ctx, u.GetName(), metav1.GetOptions{})
+}
+
+// List returns the objects of the kind in the namespace, or in all
+// namespaces when the namespace is empty.
+func (u *Unstructured) List() (*unstructured.UnstructuredList, error) {
+	resource, err := u.resource()
+	if err != nil {
+		return nil, err
+	}
+	return resource.List(u.ctx, metav1.ListOptions{})
+}
+
+func (u *Unstructured) Empty() bool {
+	_, err := u.Get()
+	return errors.IsNotFound(err)
+}
+
+func (u *Unstructured) CreateOrUpdate() error {
+	_, err := u.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return u.Create()
+		}
+		return err
+	}
+	return u.Update()
+}
+
+// Equal compares the fields at the dot separated paths, e.g. "spec.tasks",
+// with the object in the cluster, it compares spec by default.
+func (u *Unstructured) Equal(keys []string) bool {
+	if u.err != nil || u.setErr != nil {
+		return false
+	}
+	obj, err := u.Get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false
+		}
+		panic(err)
+	}
+	if len(keys) == 0 {
+		keys = []string{"spec"}
+	}
+	for _, key := range keys {
+		fields := strings.Split(key, ".")
+		a, aok, _ := unstructured.NestedFieldNoCopy(u.Object, fields...)
+		b, bok, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if aok != bok || !reflect.DeepEqual(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// resource resolves the resource of the kind, namespaced only when the kind
+// is namespaced.
+func (u *Unstructured) resource() (dynamic.ResourceInterface, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	if u.setErr != nil {
+		return nil, u.setErr
+	}
+	if u.dynamic == nil || u.mapper == nil {
+		return nil, fmt.Errorf("unstructured %s is not linked", u.GetName())
+	}
+	gvk := u.GroupVersionKind()
+	mapping, err := u.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the cached discovery does not know the CRDs installed after the
+		// first lookup, reset it and try once more
+		if mapper, ok := u.mapper.(meta.ResettableRESTMapper); ok {
+			mapper.Reset()
+			mapping, err = u.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return u.dynamic.Resource(mapping.Resource).Namespace(u.GetNamespace()), nil
+	}
+	return u.dynamic.Resource(mapping.Resource), nil
+}
@@ -0,0 +1,129 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/28 16:24:51
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestUnstructured(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"}
+	gvr := schema.GroupVersionResource{Group: "scheduling.volcano.sh", Version: "v1beta1", Resource: "podgroups"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gvk.GroupVersion()})
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "PodGroupList"})
+
+	if err := NewUnstructured(nil).TypeMeta("scheduling.volcano.sh/v1beta1", "PodGroup").Create(); err == nil {
+		t.Error("expect error of an unlinked builder")
+	}
+
+	pg := NewUnstructured(nil).LinkClient(client, mapper).
+		TypeMeta("scheduling.volcano.sh/v1beta1", "PodGroup").Metadata("train", "default").
+		Labels(map[string]string{"app": "train"}).
+		Set(8, "spec", "minMember").
+		Set("high", "spec", "priorityClassName")
+	if !pg.Empty() {
+		t.Fatal("expect podgroup not exist")
+	}
+	if err := pg.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	if !pg.Equal(nil) {
+		t.Error("expect podgroup equal after create")
+	}
+
+	pg.Set(int64(16), "spec", "minMember")
+	if pg.Equal([]string{"spec.minMember"}) {
+		t.Error("expect minMember changed")
+	}
+	if err := pg.CreateOrUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := pg.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _, _ := unstructured.NestedInt64(got.Object, "spec", "minMember"); v != 16 {
+		t.Errorf("expect minMember 16, got %d", v)
+	}
+
+	list, err := pg.List()
+	if err != nil || len(list.Items) != 1 {
+		t.Fatalf("unexpected list %v, err: %v", list, err)
+	}
+	if err := pg.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewUnstructured(nil).Set(func() {}, "spec", "replicas").Create(); err == nil {
+		t.Error("expect error of a non json value")
+	}
+	// the value is set after the fields which are not objects
+	if err := NewUnstructured(nil).Set("a", "spec").Set(1, "spec", "replicas").Err(); err == nil {
+		t.Error("expect error of setting a field in a string")
+	}
+	// linking after Set keeps the error of Set
+	broken := NewUnstructured(nil).TypeMeta("scheduling.volcano.sh/v1beta1", "PodGroup").Metadata("broken", "default").
+		Set(func() {}, "spec", "minMember").LinkClient(client, mapper)
+	if broken.Err() == nil {
+		t.Error("expect error of Set after linking")
+	}
+	if err := broken.Create(); err == nil {
+		t.Error("expect error of Set from Create")
+	}
+	if _, err := broken.Get(); err == nil {
+		t.Error("expect error of Set from Get")
+	}
+}
+
+// crdMapper knows the kind only after Reset, like a cached discovery mapper
+// before the CRD was installed.
+type crdMapper struct {
+	*meta.DefaultRESTMapper
+	gvk    schema.GroupVersionKind
+	resets int
+}
+
+func (m *crdMapper) Reset() {
+	m.resets++
+	m.Add(m.gvk, meta.RESTScopeNamespace)
+}
+
+func TestUnstructuredResetMapper(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "batch.volcano.sh", Version: "v1alpha1", Kind: "Job"}
+	gvr := schema.GroupVersionResource{Group: "batch.volcano.sh", Version: "v1alpha1", Resource: "jobs"}
+	mapper := &crdMapper{
+		DefaultRESTMapper: meta.NewDefaultRESTMapper([]schema.GroupVersion{gvk.GroupVersion()}),
+		gvk:               gvk,
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "JobList"})
+
+	job := NewUnstructured(nil).LinkClient(client, mapper).
+		TypeMeta("batch.volcano.sh/v1alpha1", "Job").Metadata("train", "default")
+	if err := job.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if mapper.resets != 1 {
+		t.Errorf("expect the mapper reset once, got %d", mapper.resets)
+	}
+	if _, err := job.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if mapper.resets != 1 {
+		t.Errorf("expect no reset once the kind is known, got %d", mapper.resets)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // type Equal struct {
@@ -67,7 +69,29 @@ func flatten(obj any) map[string]FlatteItem {
 	result := make(map[string]FlatteItem)
 	// mp := structToMap(obj)
 	var f func(any, string)
+	leaf := func(prefix string, val any, kind string) {
+		name := strings.Trim(prefix, ".")
+		// remove the inline and omitempty
+		name = strings.Replace(name, "..", ".", -1)
+		result[strings.ToLower(name)] = FlatteItem{
+			Name: name,
+			Val:  val,
+			Kind: kind,
+		}
+	}
 	f = func(o any, prefix string) {
+		// the fields of a quantity are unexported, the canonical string
+		// tells 1Gi and 8Gi apart while 1Gi and 1024Mi stay equal
+		switch q := o.(type) {
+		case resource.Quantity:
+			leaf(prefix, q.String(), "quantity")
+			return
+		case *resource.Quantity:
+			if q != nil {
+				leaf(prefix, q.String(), "quantity")
+			}
+			return
+		}
 		var mp = make(map[string]any)
 		objValue := reflect.ValueOf(o)
 		if reflect.TypeOf(o).Kind() == reflect.Ptr {
@@ -100,7 +124,12 @@ func flatten(obj any) map[string]FlatteItem {
 					continue
 				}
 				fieldValue := objValue.Field(i).Interface()
-				jsonName := field.Tag.Get("json")
+				jsonName, ok := field.Tag.Lookup("json")
+				if !ok {
+					// e.g. the fields of intstr.IntOrString, which would
+					// collide without a name
+					jsonName = field.Name
+				}
 				jsonName = strings.Replace(jsonName, ",inline", "", -1)
 				jsonName = strings.Replace(jsonName, ",omitempty", "", -1)
 				mp[jsonName] = fieldValue
@@ -110,14 +139,7 @@ func flatten(obj any) map[string]FlatteItem {
 		case reflect.Func, reflect.Chan, reflect.Complex64, reflect.Complex128, reflect.Invalid:
 			return
 		default:
-			name := strings.Trim(prefix, ".")
-			// remove the inline and omitempty
-			name = strings.Replace(name, "..", ".", -1)
-			result[strings.ToLower(name)] = FlatteItem{
-				Name: name,
-				Val:  o,
-				Kind: kind.String(),
-			}
+			leaf(prefix, o, kind.String())
 		}
 		for k, v := range mp {
 			f(v, prefix+k+".")
@@ -127,6 +149,21 @@ func flatten(obj any) map[string]FlatteItem {
 	return result
 }
 
+// mergeStringMap sets the entries of src in dst, the entries only in dst are
+// kept, e.g. the annotations added by the controllers.
+func mergeStringMap(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
 func FormatStruct(data any, indent bool) string {
 	if data == nil {
 		return ""
@@ -13,6 +13,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Volume struct {
@@ -78,3 +79,129 @@ func (v *Volume) HostPath(path string, t v1.HostPathType) *Volume {
 	}
 	return v
 }
+
+func (v *Volume) PersistentVolumeClaim(claimName string, readOnly bool) *Volume {
+	v.VolumeSource = v1.VolumeSource{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+			ClaimName: claimName,
+			ReadOnly:  readOnly,
+		},
+	}
+	return v
+}
+
+func (v *Volume) NFS(server, path string, readOnly bool) *Volume {
+	v.VolumeSource = v1.VolumeSource{
+		NFS: &v1.NFSVolumeSource{
+			Server:   server,
+			Path:     path,
+			ReadOnly: readOnly,
+		},
+	}
+	return v
+}
+
+// CSI mounts an inline ephemeral volume of the csi driver.
+func (v *Volume) CSI(driver string, readOnly bool, attributes map[string]string) *Volume {
+	v.VolumeSource = v1.VolumeSource{
+		CSI: &v1.CSIVolumeSource{
+			Driver:           driver,
+			ReadOnly:         &readOnly,
+			VolumeAttributes: attributes,
+		},
+	}
+	return v
+}
+
+func (v *Volume) DownwardAPI(items []v1.DownwardAPIVolumeFile, mode int32) *Volume {
+	v.VolumeSource = v1.VolumeSource{
+		DownwardAPI: &v1.DownwardAPIVolumeSource{
+			Items:       items,
+			DefaultMode: &mode,
+		},
+	}
+	return v
+}
+
+// Projected starts a projected volume, the sources are added by the
+// Project* methods.
+func (v *Volume) Projected(mode int32) *Volume {
+	v.VolumeSource = v1.VolumeSource{
+		Projected: &v1.ProjectedVolumeSource{
+			DefaultMode: &mode,
+		},
+	}
+	return v
+}
+
+func (v *Volume) ProjectSecret(secretName string, items []v1.KeyToPath) *Volume {
+	return v.project(v1.VolumeProjection{
+		Secret: &v1.SecretProjection{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: secretName,
+			},
+			Items: items,
+		},
+	})
+}
+
+func (v *Volume) ProjectConfigMap(configMapName string, items []v1.KeyToPath) *Volume {
+	return v.project(v1.VolumeProjection{
+		ConfigMap: &v1.ConfigMapProjection{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: configMapName,
+			},
+			Items: items,
+		},
+	})
+}
+
+func (v *Volume) ProjectDownwardAPI(items []v1.DownwardAPIVolumeFile) *Volume {
+	return v.project(v1.VolumeProjection{
+		DownwardAPI: &v1.DownwardAPIProjection{
+			Items: items,
+		},
+	})
+}
+
+// ProjectServiceAccountToken mounts a bound token of the pod's service
+// account at path, the kubelet rotates it before it expires.
+func (v *Volume) ProjectServiceAccountToken(path, audience string, expirationSeconds int64) *Volume {
+	return v.project(v1.VolumeProjection{
+		ServiceAccountToken: &v1.ServiceAccountTokenProjection{
+			Audience:          audience,
+			ExpirationSeconds: &expirationSeconds,
+			Path:              path,
+		},
+	})
+}
+
+func (v *Volume) project(projection v1.VolumeProjection) *Volume {
+	if v.VolumeSource.Projected == nil {
+		v.VolumeSource = v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{},
+		}
+	}
+	v.VolumeSource.Projected.Sources = append(v.VolumeSource.Projected.Sources, projection)
+	return v
+}
+
+// Ephemeral provisions a volume from the claim template which lives and dies
+// with the pod, the name and namespace of the claim are ignored.
+func (v *Volume) Ephemeral(claim *PersistentVolumeClaim) *Volume {
+	if claim == nil {
+		return v
+	}
+	v.VolumeSource = v1.VolumeSource{
+		Ephemeral: &v1.EphemeralVolumeSource{
+			VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      claim.PersistentVolumeClaim.Labels,
+					Annotations: claim.PersistentVolumeClaim.Annotations,
+				},
+				Spec: *claim.PersistentVolumeClaim.Spec.DeepCopy(),
+			},
+		},
+	}
+	return v
+}
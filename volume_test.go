@@ -0,0 +1,56 @@
+/*
+ @Version : 1.0
+ @Author  : steven.wong
+ @Email   : 'wangxk1991@gamil.com'
+ @Time    : 2024/06/18 11:26:40
+ Desc     :
+*/
+
+package kube
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestVolumeProjected(t *testing.T) {
+	volume := NewVolume(nil).Metadata("identity").Projected(0644).
+		ProjectSecret("ca", []v1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}}).
+		ProjectConfigMap("settings", nil).
+		ProjectDownwardAPI([]v1.DownwardAPIVolumeFile{{Path: "labels", FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.labels"}}}).
+		ProjectServiceAccountToken("token", "vault", 3600)
+	projected := volume.VolumeSource.Projected
+	if projected == nil || *projected.DefaultMode != 0644 {
+		t.Fatalf("unexpected volume source %v", volume.VolumeSource)
+	}
+	if len(projected.Sources) != 4 {
+		t.Fatalf("expect 4 sources, got %d", len(projected.Sources))
+	}
+	token := projected.Sources[3].ServiceAccountToken
+	if token == nil || token.Audience != "vault" || *token.ExpirationSeconds != 3600 || token.Path != "token" {
+		t.Errorf("unexpected service account token %v", token)
+	}
+
+	// the sources start a projected volume without Projected as well
+	volume = NewVolume(nil).NFS("10.0.0.1", "/home", false).ProjectServiceAccountToken("token", "", 600)
+	if volume.VolumeSource.NFS != nil || len(volume.VolumeSource.Projected.Sources) != 1 {
+		t.Errorf("unexpected volume source %v", volume.VolumeSource)
+	}
+}
+
+func TestVolumeEphemeral(t *testing.T) {
+	claim := NewPersistentVolumeClaim(nil).Metadata("ignored", "default").StorageClass("local").
+		Labels(map[string]string{"app": "scratch"}).
+		AccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}).Size(resource.MustParse("100Gi"))
+	volume := NewVolume(nil).Metadata("scratch").Ephemeral(claim)
+	template := volume.VolumeSource.Ephemeral.VolumeClaimTemplate
+	if template.Name != "" || template.Labels["app"] != "scratch" || *template.Spec.StorageClassName != "local" {
+		t.Errorf("unexpected claim template %v", template)
+	}
+	size := template.Spec.Resources.Requests[v1.ResourceStorage]
+	if size.String() != "100Gi" {
+		t.Errorf("expect 100Gi, got %s", size.String())
+	}
+}